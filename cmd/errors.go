@@ -0,0 +1,183 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/dip-software/go-dip-api/console"
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/dip-software/go-dip-api/iron"
+	"github.com/dip-software/go-dip-api/pki"
+	"github.com/dip-software/go-dip-api/s3creds"
+)
+
+// Exit codes of hs. These are part of the public interface, see README.md
+const (
+	exitOK               = 0
+	exitError            = 1
+	exitInvalidInput     = 2
+	exitAuthRequired     = 3
+	exitNotFound         = 4
+	exitPermissionDenied = 5
+	exitNetwork          = 6
+)
+
+type errorKind string
+
+const (
+	kindError            errorKind = "error"
+	kindInvalidInput     errorKind = "invalid_input"
+	kindAuthRequired     errorKind = "auth_required"
+	kindNotFound         errorKind = "not_found"
+	kindPermissionDenied errorKind = "permission_denied"
+	kindNetwork          errorKind = "network"
+)
+
+var exitCodes = map[errorKind]int{
+	kindError:            exitError,
+	kindInvalidInput:     exitInvalidInput,
+	kindAuthRequired:     exitAuthRequired,
+	kindNotFound:         exitNotFound,
+	kindPermissionDenied: exitPermissionDenied,
+	kindNetwork:          exitNetwork,
+}
+
+// cliError attaches an errorKind to an error so Execute can pick the exit code
+type cliError struct {
+	kind errorKind
+	err  error
+}
+
+func (e *cliError) Error() string {
+	return e.err.Error()
+}
+
+func (e *cliError) Unwrap() error {
+	return e.err
+}
+
+func newError(kind errorKind, format string, a ...interface{}) error {
+	return &cliError{kind: kind, err: fmt.Errorf(format, a...)}
+}
+
+func invalidInput(format string, a ...interface{}) error {
+	return newError(kindInvalidInput, format, a...)
+}
+
+func authRequired(format string, a ...interface{}) error {
+	return newError(kindAuthRequired, format, a...)
+}
+
+func notFound(format string, a ...interface{}) error {
+	return newError(kindNotFound, format, a...)
+}
+
+func permissionDenied(format string, a ...interface{}) error {
+	return newError(kindPermissionDenied, format, a...)
+}
+
+// statusError classifies a failed API call by its HTTP status code
+func statusError(status int, format string, a ...interface{}) error {
+	return newError(statusKind(status), format, a...)
+}
+
+func statusKind(status int) errorKind {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return kindInvalidInput
+	case http.StatusUnauthorized:
+		return kindAuthRequired
+	case http.StatusForbidden:
+		return kindPermissionDenied
+	case http.StatusNotFound:
+		return kindNotFound
+	}
+	return kindError
+}
+
+// classifyError determines the kind of err, looking through wrapped errors
+// for our own typed errors, well known go-dip-api errors and network failures
+func classifyError(err error) errorKind {
+	var cliErr *cliError
+	if errors.As(err, &cliErr) {
+		return cliErr.kind
+	}
+	switch {
+	case errors.Is(err, errIAMLoginRequired), errors.Is(err, iam.ErrMissingRefreshToken):
+		return kindAuthRequired
+	case errors.Is(err, iam.ErrNotAuthorized), errors.Is(err, console.ErrNotAuthorized):
+		return kindPermissionDenied
+	case errors.Is(err, iam.ErrNotFound), errors.Is(err, iron.ErrNotFound), errors.Is(err, s3creds.ErrNotFound):
+		return kindNotFound
+	}
+	var iamErr *iam.ErrorResponse
+	if errors.As(err, &iamErr) && iamErr.Response != nil {
+		return statusKind(iamErr.Response.StatusCode)
+	}
+	var pkiErr *pki.ErrorResponse
+	if errors.As(err, &pkiErr) && pkiErr.Response != nil {
+		return statusKind(pkiErr.Response.StatusCode)
+	}
+	var s3Err *s3creds.ErrorResponse
+	if errors.As(err, &s3Err) && s3Err.Response != nil {
+		return statusKind(s3Err.Response.StatusCode)
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	if errors.As(err, &urlErr) || errors.As(err, &netErr) {
+		return kindNetwork
+	}
+	return kindError
+}
+
+type errorEnvelope struct {
+	Error struct {
+		Kind     errorKind `json:"kind"`
+		Message  string    `json:"message"`
+		ExitCode int       `json:"exitCode"`
+	} `json:"error"`
+}
+
+// reportError writes err to stderr, as a JSON envelope when structured
+// output was requested, and returns the exit code to use
+func reportError(err error) int {
+	kind := classifyError(err)
+	code := exitCodes[kind]
+	if !tableOutput() {
+		var envelope errorEnvelope
+		envelope.Error.Kind = kind
+		envelope.Error.Message = err.Error()
+		envelope.Error.ExitCode = code
+		data, _ := json.Marshal(envelope)
+		fmt.Fprintf(os.Stderr, "%s\n", data)
+		return code
+	}
+	fmt.Fprintf(os.Stderr, "error: %v\n", err)
+	return code
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/dip-software/go-dip-api/iam"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		kind errorKind
+	}{
+		{fmt.Errorf("boom"), kindError},
+		{invalidInput("bad flag"), kindInvalidInput},
+		{fmt.Errorf("wrapped: %w", notFound("missing")), kindNotFound},
+		{fmt.Errorf("error initializing IAM client: %w", errIAMLoginRequired), kindAuthRequired},
+		{fmt.Errorf("lookup: %w", iam.ErrNotFound), kindNotFound},
+		{&iam.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}}, kindPermissionDenied},
+		{&url.Error{Op: "Get", URL: "https://example.com", Err: fmt.Errorf("connection refused")}, kindNetwork},
+		{statusError(http.StatusUnauthorized, "status %d", http.StatusUnauthorized), kindAuthRequired},
+	}
+	for _, test := range tests {
+		if kind := classifyError(test.err); kind != test.kind {
+			t.Errorf("%v: expected %s, got %s", test.err, test.kind, kind)
+		}
+	}
+}
@@ -22,8 +22,14 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/dip-software/go-dip-api/iam"
 	"github.com/spf13/cobra"
@@ -34,8 +40,8 @@ var iamCmd = &cobra.Command{
 	Use:   "iam",
 	Short: "Interact with HSDP IAM resources",
 	Long:  `Interact with HSDP IAM resources`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -55,13 +61,35 @@ func init() {
 	// iamCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
-func getIAMClient(_ *cobra.Command) (*iam.Client, error) {
-	iamClient, err := iam.NewClient(http.DefaultClient, &iam.Config{
-		Region:         currentWorkspace.IAMRegion,
-		Environment:    currentWorkspace.IAMEnvironment,
-		OAuth2ClientID: clientID,
-		OAuth2Secret:   clientSecret,
-	})
+// errIAMLoginRequired is returned when the workspace IAM session cannot be refreshed
+var errIAMLoginRequired = errors.New("IAM session expired, please login again using: hs iam login")
+
+// errNoOrgSelected is returned by commands which operate on the selected IAM organization
+var errNoOrgSelected = invalidInput("please select an organization first using: hs iam orgs select")
+
+// iamSession tracks the client handed out by getIAMClient so any tokens
+// refreshed during a command are persisted once it completes
+var iamSession *iam.Client
+
+// iamConfig returns the IAM client configuration of workspace w. Sessions
+// started using a custom OAuth2 client are introspected and refreshed using
+// that client, other sessions using the built-in hs client.
+func (w *workspaceConfig) iamConfig() *iam.Config {
+	id, secret := clientID, clientSecret
+	if w.IAMClientID != "" {
+		id, secret = w.IAMClientID, w.IAMClientSecret
+	}
+	return &iam.Config{
+		Region:         w.IAMRegion,
+		Environment:    w.IAMEnvironment,
+		OAuth2ClientID: id,
+		OAuth2Secret:   secret,
+	}
+}
+
+// newIAMClient returns an IAM client loaded with the workspace tokens as-is
+func newIAMClient() (*iam.Client, error) {
+	iamClient, err := iam.NewClient(http.DefaultClient, currentWorkspace.iamConfig())
 	if err != nil {
 		return nil, fmt.Errorf("iam client: %w", err)
 	}
@@ -71,3 +99,102 @@ func getIAMClient(_ *cobra.Command) (*iam.Client, error) {
 		currentWorkspace.IAMAccessTokenExpires)
 	return iamClient, nil
 }
+
+// getIAMClient returns an authenticated IAM client, refreshing and persisting
+// the workspace tokens first when the access token has expired
+func getIAMClient(_ *cobra.Command) (*iam.Client, error) {
+	if currentWorkspace.IAMAccessToken == "" && currentWorkspace.IAMServiceKey == "" {
+		return nil, errIAMLoginRequired
+	}
+	iamClient, err := newIAMClient()
+	if err != nil {
+		return nil, err
+	}
+	if currentWorkspace.iamLoginExpired() {
+		if err := refreshIAMSession(iamClient); err != nil {
+			return nil, err
+		}
+	}
+	iamSession = iamClient
+	return iamClient, nil
+}
+
+// refreshIAMSession refreshes the tokens of client and saves them to the workspace.
+// Service identity sessions have no refresh token and login again using the stored key.
+func refreshIAMSession(client *iam.Client) error {
+	if currentWorkspace.IAMServiceKey != "" {
+		key, err := decodeKey([]byte(currentWorkspace.IAMServiceKey))
+		if err != nil {
+			return fmt.Errorf("%w (stored service key: %v)", errIAMLoginRequired, err)
+		}
+		if err := client.ServiceLogin(iam.Service{ServiceID: key.ID, PrivateKey: key.PrivateKey}); err != nil {
+			return fmt.Errorf("%w (service login failed: %v)", errIAMLoginRequired, err)
+		}
+		if err := currentWorkspace.saveWithIAM(client); err != nil {
+			return fmt.Errorf("saving refreshed tokens: %w", err)
+		}
+		return nil
+	}
+	if currentWorkspace.IAMAccessToken == "" || currentWorkspace.IAMRefreshToken == "" {
+		return errIAMLoginRequired
+	}
+	if err := client.TokenRefresh(); err != nil {
+		return fmt.Errorf("%w (refresh failed: %v)", errIAMLoginRequired, err)
+	}
+	if err := currentWorkspace.saveWithIAM(client); err != nil {
+		return fmt.Errorf("saving refreshed tokens: %w", err)
+	}
+	return nil
+}
+
+// persistIAMSession saves tokens which were refreshed while a command ran
+func persistIAMSession() {
+	if iamSession == nil || currentWorkspace == nil {
+		return
+	}
+	if iamSession.Expires() <= currentWorkspace.IAMAccessTokenExpires {
+		return
+	}
+	if err := currentWorkspace.saveWithIAM(iamSession); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to save workspace: %v\n", err)
+	}
+}
+
+// iamIDMRequest performs an authenticated request against an IDM endpoint
+// which go-dip-api does not provide a method for. A non 2xx response is
+// returned as an error, otherwise the JSON response is decoded into out.
+func iamIDMRequest(client *iam.Client, method, path, apiVersion string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, client.BaseIDMURL().String()+path, reader)
+	if err != nil {
+		return err
+	}
+	token, err := client.Token()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Api-Version", apiVersion)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.HttpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return statusError(resp.StatusCode, "%s %s: %d %s", method, path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+// iamAccessCmd represents the access command
+var iamAccessCmd = &cobra.Command{
+	Use:   "access",
+	Short: "Explain effective access",
+	Long:  `Explains which groups and roles grant an identity its permissions.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	iamCmd.AddCommand(iamAccessCmd)
+}
+
+// accessGrant is one group -> role -> permission chain granting a permission
+type accessGrant struct {
+	Group      string `json:"group"`
+	GroupID    string `json:"groupId"`
+	Role       string `json:"role"`
+	RoleID     string `json:"roleId"`
+	Permission string `json:"permission"`
+}
+
+// accessExplainer looks up and caches the role permissions needed to explain access
+type accessExplainer struct {
+	client      *iam.Client
+	permissions map[string][]string
+}
+
+func newAccessExplainer(client *iam.Client) *accessExplainer {
+	return &accessExplainer{client: client, permissions: make(map[string][]string)}
+}
+
+func (a *accessExplainer) rolePermissions(role iam.Role) ([]string, error) {
+	if permissions, ok := a.permissions[role.ID]; ok {
+		return permissions, nil
+	}
+	permissions, err := rolePermissions(a.client, &role)
+	if err != nil {
+		return nil, err
+	}
+	a.permissions[role.ID] = permissions
+	return permissions, nil
+}
+
+// grants returns every group -> role -> permission chain of the member in org,
+// sorted by permission, group and role
+func (a *accessExplainer) grants(org, memberType, memberID string) ([]accessGrant, error) {
+	groups, _, err := a.client.Groups.GetGroups(&iam.GetGroupOptions{
+		OrganizationID: &org,
+		MemberType:     &memberType,
+		MemberID:       &memberID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving groups: %w", err)
+	}
+	grants := make([]accessGrant, 0)
+	if groups == nil {
+		return grants, nil
+	}
+	for _, group := range *groups {
+		roles, _, err := a.client.Groups.GetRoles(iam.Group{ID: group.ID})
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving roles of group '%s': %w", group.GroupName, err)
+		}
+		if roles == nil || len(*roles) == 0 {
+			grants = append(grants, accessGrant{Group: group.GroupName, GroupID: group.ID})
+			continue
+		}
+		for _, role := range *roles {
+			permissions, err := a.rolePermissions(role)
+			if err != nil {
+				return nil, err
+			}
+			if len(permissions) == 0 {
+				grants = append(grants, accessGrant{Group: group.GroupName, GroupID: group.ID, Role: role.Name, RoleID: role.ID})
+			}
+			for _, permission := range permissions {
+				grants = append(grants, accessGrant{
+					Group:      group.GroupName,
+					GroupID:    group.ID,
+					Role:       role.Name,
+					RoleID:     role.ID,
+					Permission: permission,
+				})
+			}
+		}
+	}
+	sort.SliceStable(grants, func(i, j int) bool {
+		if grants[i].Permission != grants[j].Permission {
+			return grants[i].Permission < grants[j].Permission
+		}
+		if grants[i].Group != grants[j].Group {
+			return grants[i].Group < grants[j].Group
+		}
+		return grants[i].Role < grants[j].Role
+	})
+	return grants, nil
+}
+
+// rolesGranting returns the names of the roles in org which include permission
+func (a *accessExplainer) rolesGranting(org, permission string) ([]string, error) {
+	roles, _, err := a.client.Roles.GetRoles(&iam.GetRolesOptions{OrganizationID: &org})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving roles: %w", err)
+	}
+	names := make([]string, 0)
+	if roles == nil {
+		return names, nil
+	}
+	for _, role := range *roles {
+		permissions, err := a.rolePermissions(role)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range permissions {
+			if p == permission {
+				names = append(names, role.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// grantsFor filters grants down to those granting the upper cased permission
+func grantsFor(grants []accessGrant, permission string) []accessGrant {
+	matching := make([]accessGrant, 0)
+	for _, grant := range grants {
+		if grant.Permission == permission {
+			matching = append(matching, grant)
+		}
+	}
+	return matching
+}
+
+// unexplainedPermissions returns the effective permissions no grant accounts
+// for, e.g. because they are inherited from a parent organization
+func unexplainedPermissions(effective []string, grants []accessGrant) []string {
+	explained := make(map[string]bool, len(grants))
+	for _, grant := range grants {
+		explained[grant.Permission] = true
+	}
+	missing := make([]string, 0)
+	for _, p := range effective {
+		if !explained[p] {
+			missing = append(missing, p)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func printGrants(grants []accessGrant) error {
+	rows := make([][]interface{}, 0, len(grants))
+	for _, grant := range grants {
+		rows = append(rows, []interface{}{grant.Group, grant.Role, grant.Permission})
+	}
+	return printOutput(grants, []string{"group", "role", "permission"}, rows)
+}
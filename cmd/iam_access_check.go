@@ -0,0 +1,134 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type accessCheck struct {
+	Subject      string        `json:"subject"`
+	SubjectID    string        `json:"subjectId"`
+	Permission   string        `json:"permission"`
+	Organization string        `json:"organization"`
+	Granted      bool          `json:"granted"`
+	Grants       []accessGrant `json:"grants"`
+	Groups       []string      `json:"groups"`
+	Roles        []string      `json:"rolesWithPermission,omitempty"`
+}
+
+// iamAccessCheckCmd represents the check command
+var iamAccessCheckCmd = &cobra.Command{
+	Use:   "check <identity> <permission>",
+	Short: "Check if an identity has a permission",
+	Long: `Checks whether a user, service or device has a permission in an organization
+and shows the group -> role -> permission chain which grants it.
+
+When the permission is missing the groups of the identity are listed together
+with the roles in the organization which would grant it. The command then
+exits with the permission denied exit code.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		memberType, err := memberType(cmd)
+		if err != nil {
+			return err
+		}
+		org, _ := cmd.Flags().GetString("org")
+		if org == "" {
+			org = currentWorkspace.IAMSelectedOrg
+		}
+		if org == "" {
+			return errNoOrgSelected
+		}
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+		subjectID, err := resolveMember(iamClient, memberType, args[0])
+		if err != nil {
+			return err
+		}
+		check := accessCheck{
+			Subject:      args[0],
+			SubjectID:    subjectID,
+			Permission:   strings.ToUpper(args[1]),
+			Organization: org,
+			Groups:       []string{},
+		}
+		explainer := newAccessExplainer(iamClient)
+		grants, err := explainer.grants(org, memberType, subjectID)
+		if err != nil {
+			return err
+		}
+		check.Grants = grantsFor(grants, check.Permission)
+		check.Granted = len(check.Grants) > 0
+		seen := make(map[string]bool)
+		for _, grant := range grants {
+			if !seen[grant.Group] {
+				seen[grant.Group] = true
+				check.Groups = append(check.Groups, grant.Group)
+			}
+		}
+		if !check.Granted {
+			if check.Roles, err = explainer.rolesGranting(org, check.Permission); err != nil {
+				return err
+			}
+		}
+
+		if !tableOutput() {
+			if err := printObject(check); err != nil {
+				return err
+			}
+		} else if check.Granted {
+			fmt.Printf("GRANTED: %s has %s in organization %s\n\n", check.Subject, check.Permission, org)
+			if err := printGrants(check.Grants); err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("DENIED: %s does not have %s in organization %s\n\n", check.Subject, check.Permission, org)
+			if len(check.Groups) == 0 {
+				fmt.Printf("%s is not a member of any group in this organization\n", check.Subject)
+			} else {
+				fmt.Printf("member of groups: %s\n", strings.Join(check.Groups, ", "))
+			}
+			if len(check.Roles) == 0 {
+				fmt.Printf("no role in this organization includes %s\n", check.Permission)
+			} else {
+				fmt.Printf("roles including %s: %s\n", check.Permission, strings.Join(check.Roles, ", "))
+				fmt.Printf("assign one of them to a group of %s using: hs iam groups assign-role <group> <role>\n", check.Subject)
+			}
+		}
+		if !check.Granted {
+			return permissionDenied("%s does not have %s in organization %s", check.Subject, check.Permission, org)
+		}
+		return nil
+	},
+}
+
+func init() {
+	iamAccessCmd.AddCommand(iamAccessCheckCmd)
+	iamAccessCheckCmd.Flags().String("org", "", "Organization ID (default is the selected organization)")
+	iamAccessCheckCmd.Flags().String("type", "user", "Identity type: user, service or device")
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGrantsForAndUnexplained(t *testing.T) {
+	grants := []accessGrant{
+		{Group: "admins", Role: "ADMIN", Permission: "PATIENT.READ"},
+		{Group: "admins", Role: "ADMIN", Permission: "PATIENT.WRITE"},
+		{Group: "readers", Role: "READER", Permission: "PATIENT.READ"},
+	}
+	if matching := grantsFor(grants, "PATIENT.READ"); len(matching) != 2 {
+		t.Errorf("expected 2 grants for PATIENT.READ, got %d", len(matching))
+	}
+	if matching := grantsFor(grants, "DEVICE.READ"); len(matching) != 0 {
+		t.Errorf("expected no grants for DEVICE.READ, got %d", len(matching))
+	}
+	unexplained := unexplainedPermissions([]string{"PATIENT.READ", "ORGANIZATION.READ", "DEVICE.READ"}, grants)
+	if !reflect.DeepEqual(unexplained, []string{"DEVICE.READ", "ORGANIZATION.READ"}) {
+		t.Errorf("unexpected unexplained permissions %v", unexplained)
+	}
+}
@@ -0,0 +1,282 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+// iamApplyCmd represents the apply command
+var iamApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a declarative IAM configuration",
+	Long: `Compares a desired state file with the live IAM configuration of an
+organization, shows the plan and applies it after confirmation.
+
+The file declares sub organizations, roles with their permissions and groups
+with their roles and members:
+
+  organization: my-org          # default is the selected organization
+  orgs:
+    - name: team-a
+      description: Team A
+  roles:
+    - name: READER
+      permissions: [PATIENT.READ]
+  groups:
+    - name: readers
+      roles: [READER]
+      users: [alice@example.com]
+      services: [reader@app.my-org.example.com]
+
+Without --prune the plan only creates and adds, --prune also removes the
+permissions, roles and members which are not declared and deletes the groups
+and roles of the organization which are not in the file. Organizations are
+never deleted. Role descriptions are only set when a role is created.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		if file == "" {
+			return invalidInput("specify the configuration file using --file")
+		}
+		prune, _ := cmd.Flags().GetBool("prune")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+		config, err := readIAMConfig(file)
+		if err != nil {
+			return err
+		}
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+		org, err := resolveOrg(iamClient, config.Organization)
+		if err != nil {
+			return err
+		}
+		state, err := loadIAMState(iamClient, org.ID, config, prune)
+		if err != nil {
+			return err
+		}
+		actions, err := planIAM(config, state, prune)
+		if err != nil {
+			return err
+		}
+
+		plan := struct {
+			Organization string       `json:"organization"`
+			Actions      []planAction `json:"actions"`
+			Applied      int          `json:"applied"`
+		}{Organization: org.ID, Actions: actions}
+		if tableOutput() {
+			fmt.Printf("Plan for organization %s (%s):\n\n", org.Name, org.ID)
+			for _, action := range actions {
+				fmt.Println(action)
+			}
+			if len(actions) == 0 {
+				fmt.Println("no changes, IAM matches the configuration")
+				return nil
+			}
+			fmt.Println()
+		}
+		if dryRun || len(actions) == 0 {
+			if !tableOutput() {
+				return printObject(plan)
+			}
+			return nil
+		}
+		if err := confirm(fmt.Sprintf("Apply %d change(s) to organization %s", len(actions), org.Name), yes); err != nil {
+			return err
+		}
+		applier := &iamApplier{
+			ctx:    cmd.Context(),
+			client: iamClient,
+			org:    org.ID,
+			config: config,
+			state:  state,
+		}
+		for _, action := range actions {
+			if err := applier.apply(action); err != nil {
+				if tableOutput() {
+					fmt.Fprintf(os.Stderr, "applied %d of %d changes, run apply again to continue\n", plan.Applied, len(actions))
+				}
+				return fmt.Errorf("%s: %w", action, err)
+			}
+			plan.Applied++
+		}
+		if !tableOutput() {
+			return printObject(plan)
+		}
+		fmt.Printf("applied %d change(s)\n", plan.Applied)
+		return nil
+	},
+}
+
+// iamApplier executes plan actions, keeping track of the IDs of created resources
+type iamApplier struct {
+	ctx    context.Context
+	client *iam.Client
+	org    string
+	config *iamConfig
+	state  *iamState
+}
+
+func (a *iamApplier) roleID(name string) string {
+	if role, ok := a.state.Roles[name]; ok {
+		return role.ID
+	}
+	return ""
+}
+
+func (a *iamApplier) group(name string) iam.Group {
+	if group, ok := a.state.Groups[name]; ok {
+		return iam.Group{ID: group.ID, Name: name}
+	}
+	return iam.Group{Name: name}
+}
+
+func (a *iamApplier) apply(action planAction) error {
+	var err error
+	switch action.Kind + "/" + action.Op {
+	case kindOrg + "/" + opCreate:
+		desired := a.orgConfig(action.Name)
+		_, _, err = a.client.Organizations.CreateOrganization(iam.Organization{
+			Name:        desired.Name,
+			DisplayName: desired.DisplayName,
+			Description: desired.Description,
+			Type:        desired.Type,
+			ExternalID:  desired.ExternalID,
+			Parent:      iam.Attribute{Value: a.org},
+		})
+	case kindOrg + "/" + opUpdate:
+		desired := a.orgConfig(action.Name)
+		var org *iam.Organization
+		if org, _, err = a.client.Organizations.GetOrganizationByID(a.state.Orgs[action.Name].ID); err != nil {
+			return err
+		}
+		for _, f := range []struct {
+			field *string
+			value string
+		}{
+			{&org.DisplayName, desired.DisplayName},
+			{&org.Description, desired.Description},
+			{&org.Type, desired.Type},
+			{&org.ExternalID, desired.ExternalID},
+		} {
+			if f.value != "" {
+				*f.field = f.value
+			}
+		}
+		_, _, err = a.client.Organizations.UpdateOrganization(*org)
+	case kindRole + "/" + opCreate:
+		var role *iam.Role
+		description := ""
+		for _, r := range a.config.Roles {
+			if r.Name == action.Name {
+				description = r.Description
+			}
+		}
+		if role, _, err = a.client.Roles.CreateRole(action.Name, description, a.org); err != nil {
+			return err
+		}
+		if role == nil {
+			return fmt.Errorf("role '%s' not returned after create", action.Name)
+		}
+		a.state.Roles[action.Name] = &liveRole{ID: role.ID}
+	case kindRole + "/" + opDelete:
+		_, _, err = a.client.Roles.DeleteRole(iam.Role{ID: a.roleID(action.Name), Name: action.Name})
+	case kindPermission + "/" + opAdd:
+		_, _, err = a.client.Roles.AddRolePermission(iam.Role{ID: a.roleID(action.Name)}, action.Value)
+	case kindPermission + "/" + opRemove:
+		_, _, err = a.client.Roles.RemoveRolePermission(iam.Role{ID: a.roleID(action.Name)}, action.Value)
+	case kindGroup + "/" + opCreate:
+		var group *iam.Group
+		description := ""
+		for _, g := range a.config.Groups {
+			if g.Name == action.Name {
+				description = g.Description
+			}
+		}
+		group, _, err = a.client.Groups.CreateGroup(iam.Group{
+			Name:                 action.Name,
+			Description:          description,
+			ManagingOrganization: a.org,
+		})
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return fmt.Errorf("group '%s' not returned after create", action.Name)
+		}
+		a.state.Groups[action.Name] = &liveGroup{ID: group.ID}
+	case kindGroup + "/" + opUpdate:
+		group := a.group(action.Name)
+		for _, g := range a.config.Groups {
+			if g.Name == action.Name {
+				group.Description = g.Description
+			}
+		}
+		_, _, err = a.client.Groups.UpdateGroup(group)
+	case kindGroup + "/" + opDelete:
+		err = actionOK(a.client.Groups.DeleteGroup(a.group(action.Name)))
+	case kindAssignment + "/" + opAdd:
+		err = actionOK(a.client.Groups.AssignRole(a.ctx, a.group(action.Name), iam.Role{ID: a.roleID(action.Value)}))
+	case kindAssignment + "/" + opRemove:
+		err = actionOK(a.client.Groups.RemoveRole(a.ctx, a.group(action.Name), iam.Role{ID: a.roleID(action.Value)}))
+	case kindMember + "/" + opAdd:
+		if action.MemberType == iam.GroupMemberTypeUser {
+			_, _, err = a.client.Groups.AddMembers(a.ctx, a.group(action.Name), action.MemberID)
+		} else {
+			_, _, err = a.client.Groups.AddIdentities(a.ctx, a.group(action.Name), action.MemberType, action.MemberID)
+		}
+	case kindMember + "/" + opRemove:
+		if action.MemberType == iam.GroupMemberTypeUser {
+			_, _, err = a.client.Groups.RemoveMembers(a.ctx, a.group(action.Name), action.MemberID)
+		} else {
+			_, _, err = a.client.Groups.RemoveIdentities(a.ctx, a.group(action.Name), action.MemberType, action.MemberID)
+		}
+	default:
+		err = fmt.Errorf("unsupported action %s %s", action.Op, action.Kind)
+	}
+	return err
+}
+
+func (a *iamApplier) orgConfig(name string) orgConfig {
+	for _, o := range a.config.Orgs {
+		if o.Name == name {
+			return o
+		}
+	}
+	return orgConfig{Name: name}
+}
+
+func init() {
+	iamCmd.AddCommand(iamApplyCmd)
+	iamApplyCmd.Flags().StringP("file", "f", "", "Desired state file in YAML or JSON, - reads stdin")
+	iamApplyCmd.Flags().Bool("prune", false, "Remove resources which are not in the file")
+	iamApplyCmd.Flags().Bool("dry-run", false, "Only show the plan")
+	iamApplyCmd.Flags().BoolP("yes", "y", false, "Apply without asking for confirmation")
+}
@@ -22,17 +22,30 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dip-software/go-dip-api/iam"
 	"github.com/spf13/cobra"
 )
 
+// groupMemberTypes maps the --type values to IAM group member types
+var groupMemberTypes = map[string]string{
+	"user":    iam.GroupMemberTypeUser,
+	"service": iam.GroupMemberTypeService,
+	"device":  iam.GroupMemberTypeDevice,
+}
+
 // iamGroupsCmd represents the groups command
 var iamGroupsCmd = &cobra.Command{
 	Use:     "groups",
 	Aliases: []string{"g"},
 	Short:   "IAM Group management",
 	Long:    "IAM Group management.",
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -40,3 +53,243 @@ func init() {
 	iamCmd.AddCommand(iamGroupsCmd)
 
 }
+
+// resolveGroupID returns the ID of the group named nameOrID in org. When no
+// group by that name exists nameOrID is assumed to be a group ID already.
+func resolveGroupID(client *iam.Client, org, nameOrID string) (string, error) {
+	groups, _, err := client.Groups.GetGroups(&iam.GetGroupOptions{
+		OrganizationID: &org,
+		Name:           &nameOrID,
+	})
+	if err != nil && !errors.Is(err, iam.ErrEmptyResults) {
+		return "", fmt.Errorf("error looking up group '%s': %w", nameOrID, err)
+	}
+	if groups == nil || len(*groups) == 0 {
+		return nameOrID, nil
+	}
+	if len(*groups) > 1 {
+		return "", invalidInput("group name '%s' is ambiguous, use the group ID instead", nameOrID)
+	}
+	return (*groups)[0].ID, nil
+}
+
+// lookupGroup finds the group named nameOrID in org, falling back to a lookup by ID
+func lookupGroup(client *iam.Client, org, nameOrID string) (*iam.Group, error) {
+	id, err := resolveGroupID(client, org, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	group, _, err := client.Groups.GetGroupByID(id)
+	if err != nil {
+		if classifyError(err) == kindNotFound {
+			return nil, notFound("group '%s' not found", nameOrID)
+		}
+		return nil, fmt.Errorf("error retrieving group '%s': %w", nameOrID, err)
+	}
+	group.ID = id
+	return group, nil
+}
+
+// groupArgs returns the group given as first argument, requiring a selected organization
+func groupArgs(cmd *cobra.Command, args []string) (*iam.Client, *iam.Group, error) {
+	if currentWorkspace.IAMSelectedOrg == "" {
+		return nil, nil, errNoOrgSelected
+	}
+	iamClient, err := getIAMClient(cmd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error initalizing IAM client: %w", err)
+	}
+	group, err := lookupGroup(iamClient, currentWorkspace.IAMSelectedOrg, args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	return iamClient, group, nil
+}
+
+// memberType returns the IAM member type selected with the --type flag
+func memberType(cmd *cobra.Command) (string, error) {
+	name, _ := cmd.Flags().GetString("type")
+	memberType, ok := groupMemberTypes[strings.ToLower(name)]
+	if !ok {
+		return "", invalidInput("unknown member type '%s', use user, service or device", name)
+	}
+	return memberType, nil
+}
+
+// resolveMember returns the UUID of a group member. Users are found by login ID
+// or UUID, services by service ID or UUID and devices by login ID or UUID.
+func resolveMember(client *iam.Client, memberType, ref string) (string, error) {
+	switch memberType {
+	case iam.GroupMemberTypeUser:
+		user, err := lookupUser(client, ref)
+		if err != nil {
+			return "", err
+		}
+		return user.ID, nil
+	case iam.GroupMemberTypeService:
+		service, err := lookupService(client, ref)
+		if err != nil {
+			return "", err
+		}
+		return service.ID, nil
+	case iam.GroupMemberTypeDevice:
+		opts := &iam.GetDevicesOptions{LoginID: &ref}
+		if uuidPattern.MatchString(ref) {
+			opts = &iam.GetDevicesOptions{ID: &ref}
+		}
+		devices, _, err := client.Devices.GetDevices(opts)
+		if err != nil {
+			return "", fmt.Errorf("error looking up device '%s': %w", ref, err)
+		}
+		if devices == nil || len(*devices) == 0 {
+			return "", notFound("device '%s' not found", ref)
+		}
+		return (*devices)[0].ID, nil
+	}
+	return "", invalidInput("unsupported member type '%s'", memberType)
+}
+
+type groupChangeResult struct {
+	Name   string `json:"name"`
+	ID     string `json:"id,omitempty"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// printGroupChanges reports the outcome per member or role and fails when any of them failed
+func printGroupChanges(noun string, results []groupChangeResult, firstErr error) error {
+	rows := make([][]interface{}, 0, len(results))
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+		rows = append(rows, []interface{}{result.Name, result.ID, result.Result, result.Error})
+	}
+	if err := printOutput(results, []string{noun, "id", "result", "error"}, rows); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return &cliError{kind: classifyError(firstErr), err: fmt.Errorf("%d of %d %ss failed", failed, len(results), noun)}
+	}
+	return nil
+}
+
+// changeGroupMembers adds or removes the members given after the group argument
+func changeGroupMembers(cmd *cobra.Command, args []string, add bool) error {
+	memberType, err := memberType(cmd)
+	if err != nil {
+		return err
+	}
+	client, group, err := groupArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	results, firstErr := changeMembers(cmd.Context(), client, group, memberType, add, args[1:])
+	return printGroupChanges("member", results, firstErr)
+}
+
+// groupMemberChunk is the number of members IAM changes per request
+const groupMemberChunk = 10
+
+// changeMembers resolves refs to members of memberType and adds them to or
+// removes them from group. Failures do not stop processing, the first error is
+// returned along with the per member results.
+func changeMembers(ctx context.Context, client *iam.Client, group *iam.Group, memberType string, add bool, refs []string) ([]groupChangeResult, error) {
+	verb := "removed"
+	if add {
+		verb = "added"
+	}
+	results := make([]groupChangeResult, 0, len(refs))
+	resolved := make([]int, 0, len(refs))
+	var firstErr error
+	for _, ref := range refs {
+		result := groupChangeResult{Name: ref, Result: verb}
+		id, err := resolveMember(client, memberType, ref)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			result.Result = "failed"
+			result.Error = err.Error()
+		} else {
+			result.ID = id
+			resolved = append(resolved, len(results))
+		}
+		results = append(results, result)
+	}
+	// go-dip-api chunks changes itself but stops at the first failing chunk
+	// without telling which members were changed. Identity changes also reuse
+	// the group version (If-Match) read before the first chunk, which is stale
+	// for every later chunk. Passing one chunk per call gives each chunk a
+	// fresh version and lets failures be reported for their own members only.
+	for start := 0; start < len(resolved); start += groupMemberChunk {
+		chunk := resolved[start:]
+		if len(chunk) > groupMemberChunk {
+			chunk = chunk[:groupMemberChunk]
+		}
+		ids := make([]string, 0, len(chunk))
+		for _, i := range chunk {
+			ids = append(ids, results[i].ID)
+		}
+		var err error
+		switch {
+		case memberType == iam.GroupMemberTypeUser && add:
+			_, _, err = client.Groups.AddMembers(ctx, *group, ids...)
+		case memberType == iam.GroupMemberTypeUser:
+			_, _, err = client.Groups.RemoveMembers(ctx, *group, ids...)
+		case add:
+			_, _, err = client.Groups.AddIdentities(ctx, *group, memberType, ids...)
+		default:
+			_, _, err = client.Groups.RemoveIdentities(ctx, *group, memberType, ids...)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			for _, i := range chunk {
+				results[i].Result = "failed"
+				results[i].Error = err.Error()
+			}
+		}
+	}
+	return results, firstErr
+}
+
+// changeGroupRoles assigns or removes the roles given after the group argument
+func changeGroupRoles(cmd *cobra.Command, args []string, assign bool) error {
+	client, group, err := groupArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	verb := "removed"
+	if assign {
+		verb = "assigned"
+	}
+	results := make([]groupChangeResult, 0, len(args)-1)
+	var firstErr error
+	for _, ref := range args[1:] {
+		result := groupChangeResult{Name: ref, Result: verb}
+		role, err := resolveRole(client, currentWorkspace.IAMSelectedOrg, ref)
+		if err == nil {
+			result.ID = role.ID
+			var ok bool
+			var resp *iam.Response
+			if assign {
+				ok, resp, err = client.Groups.AssignRole(cmd.Context(), *group, *role)
+			} else {
+				ok, resp, err = client.Groups.RemoveRole(cmd.Context(), *group, *role)
+			}
+			err = actionOK(ok, resp, err)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			result.Result = "failed"
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return printGroupChanges("role", results, firstErr)
+}
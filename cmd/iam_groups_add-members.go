@@ -0,0 +1,45 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// iamGroupsAddMembersCmd represents the add-members command
+var iamGroupsAddMembersCmd = &cobra.Command{
+	Use:   "add-members <group> <member...>",
+	Short: "Add members to a group",
+	Long: `Adds users, services or devices to a group.
+
+Members are users unless --type is given. Users are identified by login ID or
+UUID, services by service ID or UUID and devices by login ID or UUID.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeGroupMembers(cmd, args, true)
+	},
+}
+
+func init() {
+	iamGroupsCmd.AddCommand(iamGroupsAddMembersCmd)
+	iamGroupsAddMembersCmd.Flags().String("type", "user", "Member type: user, service or device")
+}
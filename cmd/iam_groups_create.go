@@ -0,0 +1,63 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+// iamGroupsCreateCmd represents the create command
+var iamGroupsCreateCmd = &cobra.Command{
+	Use:     "create <name>",
+	Aliases: []string{"c", "new"},
+	Short:   "Create a group",
+	Long:    `Creates a group in the selected organization.`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if currentWorkspace.IAMSelectedOrg == "" {
+			return errNoOrgSelected
+		}
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+		group := iam.Group{
+			Name:                 args[0],
+			ManagingOrganization: currentWorkspace.IAMSelectedOrg,
+		}
+		group.Description, _ = cmd.Flags().GetString("description")
+		created, _, err := iamClient.Groups.CreateGroup(group)
+		if err != nil {
+			return fmt.Errorf("error creating group '%s': %w", group.Name, err)
+		}
+		return printOutput(created, []string{"group", "id", "description"},
+			[][]interface{}{{created.Name, created.ID, created.Description}})
+	},
+}
+
+func init() {
+	iamGroupsCmd.AddCommand(iamGroupsCreateCmd)
+	iamGroupsCreateCmd.Flags().String("description", "", "Description")
+}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// iamGroupsDeleteCmd represents the delete command
+var iamGroupsDeleteCmd = &cobra.Command{
+	Use:     "delete <group>",
+	Aliases: []string{"rm"},
+	Short:   "Delete a group",
+	Long: `Deletes a group, identified by name or ID.
+
+IAM only deletes groups without members and roles.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iamClient, group, err := groupArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		yes, _ := cmd.Flags().GetBool("yes")
+		if err := confirm(fmt.Sprintf("Delete group '%s' (%s)", group.Name, group.ID), yes); err != nil {
+			return err
+		}
+		if err := actionOK(iamClient.Groups.DeleteGroup(*group)); err != nil {
+			return fmt.Errorf("error deleting group '%s': %w", group.Name, err)
+		}
+		return printOutput(group, []string{"group", "id", "result"}, [][]interface{}{{group.Name, group.ID, "deleted"}})
+	},
+}
+
+func init() {
+	iamGroupsCmd.AddCommand(iamGroupsDeleteCmd)
+	iamGroupsDeleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+}
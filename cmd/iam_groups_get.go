@@ -0,0 +1,58 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+// iamGroupsGetCmd represents the get command
+var iamGroupsGetCmd = &cobra.Command{
+	Use:   "get <group>",
+	Short: "Show a group",
+	Long:  `Shows a group, identified by name or ID, and the roles assigned to it.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iamClient, group, err := groupArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		roles, _, err := iamClient.Groups.GetRoles(*group)
+		if err != nil {
+			return fmt.Errorf("error retrieving roles of group '%s': %w", group.Name, err)
+		}
+		if roles == nil {
+			roles = &[]iam.Role{}
+		}
+		return printObject(struct {
+			*iam.Group
+			Roles []iam.Role `json:"roles"`
+		}{group, *roles})
+	},
+}
+
+func init() {
+	iamGroupsCmd.AddCommand(iamGroupsGetCmd)
+}
@@ -22,10 +22,8 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
-	"github.com/cheynewallace/tabby"
 	"github.com/dip-software/go-dip-api/iam"
 	"github.com/spf13/cobra"
 )
@@ -36,11 +34,10 @@ var iamListGroupsCmd = &cobra.Command{
 	Aliases: []string{"l"},
 	Short:   "List IAM groups",
 	Long:    `List all IAM groups.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		iamClient, err := getIAMClient(cmd)
 		if err != nil {
-			fmt.Printf("error initalizing IAM client: %v\n", err)
-			return
+			return fmt.Errorf("error initalizing IAM client: %w", err)
 		}
 		opts := &iam.GetGroupOptions{}
 		if name, err := cmd.Flags().GetString("name"); err == nil && name != "" {
@@ -51,30 +48,21 @@ var iamListGroupsCmd = &cobra.Command{
 		}
 		if opts.OrganizationID == nil || *opts.OrganizationID == "" {
 			if currentWorkspace.IAMSelectedOrg == "" {
-				fmt.Printf("Select an organization first\n")
-				return
+				return errNoOrgSelected
 			}
 			opts.OrganizationID = &currentWorkspace.IAMSelectedOrg
 		}
 		groups, _, err := iamClient.Groups.GetGroups(opts)
 		if err != nil {
-			fmt.Printf("error retrieving groups: %v\n", err)
-			return
+			return fmt.Errorf("error retrieving groups: %w", err)
 		}
-		if jsonOut {
-			data, _ := json.Marshal(*groups)
-			fmt.Printf("%s\n", string(data))
-			return
-		}
-		t := tabby.New()
-		t.AddHeader("group", "id", "description")
+		rows := make([][]interface{}, 0)
 		for _, group := range *groups {
-			t.AddLine(group.GroupName,
+			rows = append(rows, []interface{}{group.GroupName,
 				group.ID,
-				group.GroupDescription)
+				group.GroupDescription})
 		}
-		t.Print()
-		_ = currentWorkspace.saveWithIAM(iamClient)
+		return printOutput(*groups, []string{"group", "id", "description"}, rows)
 	},
 }
 
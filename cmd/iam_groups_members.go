@@ -0,0 +1,92 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+type groupMember struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// iamGroupsMembersCmd represents the members command
+var iamGroupsMembersCmd = &cobra.Command{
+	Use:   "members <group>",
+	Short: "List group members",
+	Long: `Lists the users, services and devices in a group, identified by name or ID.
+
+Use --type to only list members of one type.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		types := []string{iam.GroupMemberTypeUser, iam.GroupMemberTypeService, iam.GroupMemberTypeDevice}
+		if cmd.Flags().Changed("type") {
+			memberType, err := memberType(cmd)
+			if err != nil {
+				return err
+			}
+			types = []string{memberType}
+		}
+		iamClient, group, err := groupArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		members := make([]groupMember, 0)
+		for _, memberType := range types {
+			memberType := memberType
+			scimGroup, _, err := iamClient.Groups.SCIMGetGroupByIDAll(group.ID, &iam.SCIMGetGroupOptions{
+				IncludeGroupMembersType: &memberType,
+			})
+			if err != nil {
+				return fmt.Errorf("error retrieving %s members of group '%s': %w", strings.ToLower(memberType), group.Name, err)
+			}
+			for _, resource := range scimGroup.ExtensionGroup.GroupMembers.Resources {
+				member := groupMember{Type: strings.ToLower(memberType), ID: resource.ID}
+				switch memberType {
+				case iam.GroupMemberTypeUser:
+					member.Name = resource.UserName
+				case iam.GroupMemberTypeService:
+					member.Name = resource.ServiceId
+				case iam.GroupMemberTypeDevice:
+					member.Name = resource.LoginID
+				}
+				members = append(members, member)
+			}
+		}
+		rows := make([][]interface{}, 0, len(members))
+		for _, member := range members {
+			rows = append(rows, []interface{}{member.Type, member.Name, member.ID})
+		}
+		return printOutput(members, []string{"type", "name", "id"}, rows)
+	},
+}
+
+func init() {
+	iamGroupsCmd.AddCommand(iamGroupsMembersCmd)
+	iamGroupsMembersCmd.Flags().String("type", "", "Only list members of this type: user, service or device")
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dip-software/go-dip-api/iam"
+)
+
+const testDeviceUUID = "6e0a5ad8-3b4c-4f3e-9c49-0d5c0bd1f7a1"
+
+// newFakeGroupServer serves user and device lookups and identity changes of
+// group g1. Identity changes require the current group version and fail for
+// device-bad. The sizes of the accepted and rejected chunks are recorded.
+func newFakeGroupServer(t *testing.T, chunks *[]int) *iam.Client {
+	var mu sync.Mutex
+	version := 1
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize/identity/User", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		users := []iam.User{}
+		if r.URL.Query().Get("userId") == "alice" {
+			users = append(users, iam.User{ID: "uuid-alice", LoginID: "alice"})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"total": len(users), "entry": users})
+	})
+	mux.HandleFunc("/authorize/identity/Device", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		devices := []iam.Device{}
+		if id := r.URL.Query().Get("_id"); id != "" {
+			devices = append(devices, iam.Device{ID: id})
+		} else if login := r.URL.Query().Get("loginId"); strings.HasPrefix(login, "dev-") {
+			devices = append(devices, iam.Device{ID: "device-" + strings.TrimPrefix(login, "dev-")})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"total": len(devices), "entry": devices})
+	})
+	mux.HandleFunc("/authorize/identity/Group", func(w http.ResponseWriter, r *http.Request) {
+		entries := []map[string]interface{}{}
+		switch r.URL.Query().Get("name") {
+		case "forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			return
+		case "group":
+			entries = append(entries, map[string]interface{}{"resource": iam.GroupResource{ID: "g1", GroupName: "group"}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"total": len(entries), "entry": entries})
+	})
+	mux.HandleFunc("/authorize/identity/Group/g1", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", fmt.Sprintf("v%d", version))
+		_ = json.NewEncoder(w).Encode(iam.Group{ID: "g1", Name: "group"})
+	})
+	mux.HandleFunc("/authorize/identity/Group/g1/$assign", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var body struct {
+			Value []string `json:"value"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		*chunks = append(*chunks, len(body.Value))
+		if r.Header.Get("If-Match") != fmt.Sprintf("v%d", version) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		for _, id := range body.Value {
+			if id == "device-bad" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		version++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client, err := iam.NewClient(http.DefaultClient, &iam.Config{
+		OAuth2ClientID: "client",
+		OAuth2Secret:   "secret",
+		IAMURL:         server.URL,
+		IDMURL:         server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetTokens("token", "refresh", "", time.Now().Add(time.Hour).Unix())
+	return client
+}
+
+func TestResolveMember(t *testing.T) {
+	client := newFakeGroupServer(t, &[]int{})
+	for _, tc := range []struct {
+		memberType, ref, id string
+		kind                errorKind
+	}{
+		{iam.GroupMemberTypeUser, "alice", "uuid-alice", ""},
+		{iam.GroupMemberTypeUser, "bob", "", kindNotFound},
+		{iam.GroupMemberTypeDevice, "dev-1", "device-1", ""},
+		{iam.GroupMemberTypeDevice, testDeviceUUID, testDeviceUUID, ""},
+		{iam.GroupMemberTypeDevice, "missing", "", kindNotFound},
+		{"ROBOT", "r2d2", "", kindInvalidInput},
+	} {
+		id, err := resolveMember(client, tc.memberType, tc.ref)
+		if tc.kind != "" {
+			if err == nil || classifyError(err) != tc.kind {
+				t.Errorf("%s %s: expected %s error, got %v", tc.memberType, tc.ref, tc.kind, err)
+			}
+			continue
+		}
+		if err != nil || id != tc.id {
+			t.Errorf("%s %s: expected %s, got %s %v", tc.memberType, tc.ref, tc.id, id, err)
+		}
+	}
+}
+
+func TestChangeMembersPerChunk(t *testing.T) {
+	var chunks []int
+	client := newFakeGroupServer(t, &chunks)
+	refs := make([]string, 0)
+	for i := 0; i < 23; i++ {
+		refs = append(refs, fmt.Sprintf("dev-%d", i))
+	}
+	refs[21] = "dev-bad"
+	refs = append(refs, "missing")
+
+	results, err := changeMembers(context.Background(), client, &iam.Group{ID: "g1"}, iam.GroupMemberTypeDevice, true, refs)
+	if err == nil {
+		t.Error("expected an error for the failed members")
+	}
+	if fmt.Sprint(chunks) != "[10 10 3]" {
+		t.Errorf("expected chunks of 10 with a fresh group version each, got %v", chunks)
+	}
+	for i, result := range results {
+		expected := "added"
+		if i >= 20 {
+			expected = "failed"
+		}
+		if result.Result != expected {
+			t.Errorf("%s: expected %s, got %s (%s)", result.Name, expected, result.Result, result.Error)
+		}
+	}
+}
+
+func TestResolveGroupID(t *testing.T) {
+	client := newFakeGroupServer(t, &[]int{})
+	for ref, expected := range map[string]string{"group": "g1", "g2": "g2"} {
+		if id, err := resolveGroupID(client, "org", ref); err != nil || id != expected {
+			t.Errorf("%s: expected %s, got %s %v", ref, expected, id, err)
+		}
+	}
+	if _, err := resolveGroupID(client, "org", "forbidden"); err == nil {
+		t.Error("expected lookup errors to be returned instead of using the name as ID")
+	}
+}
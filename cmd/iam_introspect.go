@@ -22,9 +22,7 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"time"
 
 	"github.com/dip-software/go-dip-api/iam"
@@ -38,50 +36,26 @@ var iamIntrospectCmd = &cobra.Command{
 	Aliases: []string{"in", "intro"},
 	Short:   "Introspect using current token",
 	Long:    `Does an introspect call with the current active token`,
-	Run: func(cmd *cobra.Command, args []string) {
-		iamClient, err := iam.NewClient(http.DefaultClient, &iam.Config{
-			Region:         currentWorkspace.IAMRegion,
-			Environment:    currentWorkspace.IAMEnvironment,
-			OAuth2ClientID: clientID,
-			OAuth2Secret:   clientSecret,
-		})
-		if err != nil {
-			fmt.Printf("error initializing IAM client: %v\n", err)
-			return
-		}
-		tempToken := false
-		useExpires := time.Now().Add(30 * time.Minute).Unix()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var iamClient *iam.Client
+		var err error
 		useToken, _ := cmd.Flags().GetString("token")
 		if useToken != "" {
-			tempToken = true
+			iamClient, err = newIAMClient()
+			if err == nil {
+				iamClient.SetTokens(useToken, "", "", time.Now().Add(30*time.Minute).Unix())
+			}
 		} else {
-			useToken = currentWorkspace.IAMAccessToken
-			useExpires = currentWorkspace.IAMAccessTokenExpires
+			iamClient, err = getIAMClient(cmd)
 		}
-
-		iamClient.SetTokens(useToken,
-			currentWorkspace.IAMRefreshToken,
-			currentWorkspace.IAMIDToken,
-			useExpires)
-		introspect, _, err := iamClient.Introspect()
 		if err != nil {
-			fmt.Printf("error performing introspect: %v\n", err)
-			return
+			return fmt.Errorf("error initializing IAM client: %w", err)
 		}
-		data, err := json.Marshal(introspect)
+		introspect, _, err := iamClient.Introspect()
 		if err != nil {
-			fmt.Printf("error marshalling introspect result: %v\n", err)
-			return
-		}
-		if !tempToken && introspect.Expires > currentWorkspace.IAMAccessTokenExpires {
-			token, _ := iamClient.Token()
-			currentWorkspace.IAMAccessToken = token
-			currentWorkspace.IAMRefreshToken = iamClient.RefreshToken()
-			currentWorkspace.IAMAccessTokenExpires = iamClient.Expires()
-			currentWorkspace.IAMIDToken = iamClient.IDToken()
-			_ = currentWorkspace.save()
+			return fmt.Errorf("error performing introspect: %w", err)
 		}
-		fmt.Println(pretty(data))
+		return printObject(introspect)
 	},
 }
 
@@ -23,11 +23,21 @@ THE SOFTWARE.
 */
 
 import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
-	"github.com/spf13/cobra"
+	"encoding/pem"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"strings"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
 )
 
 // Key is a struct that matches the JSON response structure
@@ -39,12 +49,138 @@ type Key struct {
 	Environment string `json:"e"`
 }
 
+// decodeKey parses a key file produced by keygen. Both the base64 encoded
+// key and its decoded JSON form are accepted.
+func decodeKey(data []byte) (*Key, error) {
+	data = bytes.TrimSpace(data)
+	decoded, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		// an already base64 decoded key was passed
+		decoded = data
+	}
+	var key Key
+	if err := json.Unmarshal(decoded, &key); err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+	if key.ID == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("invalid key: missing service ID or private key")
+	}
+	return &key, nil
+}
+
+// readKeyData reads a key file, or the key in HS_SERVICE_KEY when file is empty
+func readKeyData(file string) ([]byte, error) {
+	if file == "" {
+		return []byte(os.Getenv("HS_SERVICE_KEY")), nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, invalidInput("error reading key file: %v", err)
+	}
+	return data, nil
+}
+
+// privateKey parses the PEM encoded private key of the key
+func (k Key) privateKey() (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(iam.FixPEM(k.PrivateKey)))
+	if block == nil || len(block.Bytes) == 0 {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T, IAM services use RSA keys", parsed)
+	}
+	return key, nil
+}
+
+// keyFingerprint returns the SHA256 fingerprint of the DER encoded public key
+func keyFingerprint(key *rsa.PrivateKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:]), nil
+}
+
+// newServiceKey packages privateKey as a key for service in region and environment
+func newServiceKey(serviceID, region, environment string, privateKey *rsa.PrivateKey) Key {
+	return Key{
+		Version: "1",
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+		})),
+		ID:          serviceID,
+		Region:      region,
+		Environment: environment,
+	}
+}
+
+type keyVerification struct {
+	ServiceID   string   `json:"serviceId"`
+	Region      string   `json:"region"`
+	Environment string   `json:"environment"`
+	Expires     int64    `json:"expires"`
+	Scopes      []string `json:"scopes,omitempty"`
+}
+
+// verifyKey performs a service login using key. The token scopes are only
+// known when the OAuth2 client credentials allow introspection.
+func verifyKey(key *Key) (*keyVerification, error) {
+	if key.Region == "" || key.Environment == "" {
+		return nil, invalidInput("key has no region or environment")
+	}
+	client, err := iam.NewClient(http.DefaultClient, &iam.Config{
+		Region:         key.Region,
+		Environment:    key.Environment,
+		OAuth2ClientID: clientID,
+		OAuth2Secret:   clientSecret,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing IAM client: %w", err)
+	}
+	if err := client.ServiceLogin(iam.Service{ServiceID: key.ID, PrivateKey: key.PrivateKey}); err != nil {
+		return nil, authRequired("service login as %s failed: %v", key.ID, err)
+	}
+	verification := &keyVerification{
+		ServiceID:   key.ID,
+		Region:      key.Region,
+		Environment: key.Environment,
+		Expires:     client.Expires(),
+	}
+	if client.HasOAuth2Credentials() {
+		introspect, _, err := client.Introspect()
+		if err != nil {
+			return nil, fmt.Errorf("error performing introspect: %w", err)
+		}
+		verification.Scopes = strings.Fields(introspect.Scope)
+	}
+	return verification, nil
+}
+
+// encode returns the key in the base64 encoded key file format
+func (k Key) encode() (string, error) {
+	data, err := json.Marshal(k)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
 // iamKeygenCmd represents the token command
 var iamKeygenCmd = &cobra.Command{
 	Use:   "keygen",
 	Short: "Packages service credentials as a key file",
 	Long:  `Use this command to package service credentials into a key file.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Configure log output
 		logLevel := &slog.LevelVar{}
 		logLevel.Set(slog.LevelInfo)
@@ -65,8 +201,7 @@ var iamKeygenCmd = &cobra.Command{
 			environment = os.Getenv("HSP_IAM_ENVIRONMENT")
 		}
 		if region == "" || environment == "" {
-			slog.Error("region and environment must be set")
-			return
+			return invalidInput("region and environment must be set")
 		}
 		tokenFile, _ := cmd.Flags().GetString("token-file")
 
@@ -81,18 +216,15 @@ var iamKeygenCmd = &cobra.Command{
 		privateKeyFile, _ := cmd.Flags().GetString("private-key-file")
 
 		if privateKeyFile == "" {
-			slog.Error("private-key-file is required")
-			return
+			return invalidInput("private-key-file is required")
 		}
 		if serviceID == "" {
-			slog.Error("service-id is required")
-			return
+			return invalidInput("service-id is required")
 		}
 
 		key, err := os.ReadFile(privateKeyFile)
 		if err != nil {
-			slog.Error("error reading private key", "error", err)
-			return
+			return invalidInput("error reading private key: %w", err)
 		}
 		// Serialize it
 		keyData := Key{
@@ -102,21 +234,18 @@ var iamKeygenCmd = &cobra.Command{
 			Region:      region,
 			Environment: environment,
 		}
-		token, err := json.Marshal(keyData)
+		base64Token, err := keyData.encode()
 		if err != nil {
-			slog.Error("error marshalling key", "error", err)
-			return
+			return err
 		}
 
-		base64Token := base64.StdEncoding.EncodeToString(token)
-
 		// Write the key file
 		err = os.WriteFile(tokenFile, []byte(base64Token), 0644)
 		if err != nil {
-			slog.Error("error writing token file", "error", err)
-		} else {
-			slog.Info("token written", "file", tokenFile)
+			return fmt.Errorf("error writing token file: %w", err)
 		}
+		slog.Info("token written", "file", tokenFile)
+		return nil
 	},
 }
 
@@ -0,0 +1,99 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+type keyInfo struct {
+	Version     string `json:"version"`
+	ServiceID   string `json:"serviceId"`
+	Region      string `json:"region"`
+	Environment string `json:"environment"`
+	KeySize     int    `json:"keySize,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Valid       bool   `json:"valid"`
+	Error       string `json:"error,omitempty"`
+}
+
+// iamKeygenInspectCmd represents the keygen inspect command
+var iamKeygenInspectCmd = &cobra.Command{
+	Use:   "inspect [key-file]",
+	Short: "Show the contents of a key file",
+	Long: `Decodes a key file and shows the service ID, region, environment and the
+fingerprint of the key. The key is read from HS_SERVICE_KEY when no file is given.
+Exits with an error when the private key does not parse.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := keyArg(args)
+		if err != nil {
+			return err
+		}
+		info := keyInfo{
+			Version:     key.Version,
+			ServiceID:   key.ID,
+			Region:      key.Region,
+			Environment: key.Environment,
+		}
+		privateKey, keyErr := key.privateKey()
+		if keyErr == nil {
+			info.KeySize = privateKey.N.BitLen()
+			info.Fingerprint, keyErr = keyFingerprint(privateKey)
+		}
+		if keyErr != nil {
+			info.Error = keyErr.Error()
+		}
+		info.Valid = keyErr == nil
+		if err := printObject(info); err != nil {
+			return err
+		}
+		if keyErr != nil {
+			return invalidInput("invalid private key for %s: %v", key.ID, keyErr)
+		}
+		return nil
+	},
+}
+
+// keyArg decodes the key file in args, or the key in HS_SERVICE_KEY
+func keyArg(args []string) (*Key, error) {
+	file := ""
+	if len(args) > 0 {
+		file = args[0]
+	}
+	data, err := readKeyData(file)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, invalidInput("specify a key file or set HS_SERVICE_KEY")
+	}
+	key, err := decodeKey(data)
+	if err != nil {
+		return nil, invalidInput("%v", err)
+	}
+	return key, nil
+}
+
+func init() {
+	iamKeygenCmd.AddCommand(iamKeygenInspectCmd)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// iamKeygenVerifyCmd represents the keygen verify command
+var iamKeygenVerifyCmd = &cobra.Command{
+	Use:   "verify [key-file]",
+	Short: "Verify a key file by logging in with it",
+	Long: `Performs a service login using a key file and reports the token expiry and,
+for official binaries, the scopes of the token. The key is read from
+HS_SERVICE_KEY when no file is given. The region and environment of the key
+can be overridden using --region and --environment.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := keyArg(args)
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("region") {
+			key.Region, _ = cmd.Flags().GetString("region")
+		}
+		if cmd.Flags().Changed("environment") {
+			key.Environment, _ = cmd.Flags().GetString("environment")
+		}
+		if _, err := key.privateKey(); err != nil {
+			return invalidInput("invalid private key for %s: %v", key.ID, err)
+		}
+		verification, err := verifyKey(key)
+		if err != nil {
+			return err
+		}
+		return printObject(verification)
+	},
+}
+
+func init() {
+	iamKeygenCmd.AddCommand(iamKeygenVerifyCmd)
+}
@@ -23,17 +23,11 @@ THE SOFTWARE.
 */
 
 import (
-	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
-	"time"
 
 	"github.com/dip-software/go-dip-api/iam"
-
-	"github.com/labstack/echo/v4"
-	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
 )
 
@@ -45,8 +39,26 @@ type tokenOutput struct {
 var iamLoginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Log into HSDP IAM using browser authentication flow",
-	Long:  `Log into HSDP IAM using browser authentication flow`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Log into HSDP IAM using browser authentication flow.
+
+On machines without a browser, e.g. over SSH or in a container, use
+--no-browser to print the login URL and paste the URL you are redirected to
+after logging in. When the IAM client allows the device authorization grant,
+--device shows a code to enter on any device instead.
+
+The browser flow uses PKCE and a random state. The callback listens on
+localhost port 35444, the redirect URI registered for hs. Use --client-id to
+login with your own OAuth2 client, its secret is read from HS_IAM_CLIENT_SECRET
+when it has one. The client is kept in the workspace to refresh the session.
+With --client-id a free port is selected when 35444 is taken, or use
+--callback-port to pick one matching a redirect URI registered for the client.
+
+Service identities login using --service-id and --private-key-file, or using
+a key file created by 'hs iam keygen' with --key-file or the HS_SERVICE_KEY
+environment variable. The region and environment are taken from the key file
+and the identity is kept in the workspace, so hs logs in again automatically
+when the access token expires.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		region, _ := cmd.Flags().GetString("region")
 		environment, _ := cmd.Flags().GetString("environment")
 		if region == "" {
@@ -55,7 +67,20 @@ var iamLoginCmd = &cobra.Command{
 		if environment == "" {
 			environment = currentWorkspace.DefaultEnvironment
 		}
+		serviceKey, err := loginServiceKey(cmd)
+		if err != nil {
+			return err
+		}
 		serviceID, _ := cmd.Flags().GetString("service-id")
+		if serviceKey != nil {
+			serviceID = serviceKey.ID
+			if !cmd.Flags().Changed("region") && serviceKey.Region != "" {
+				region = serviceKey.Region
+			}
+			if !cmd.Flags().Changed("environment") && serviceKey.Environment != "" {
+				environment = serviceKey.Environment
+			}
+		}
 		if serviceID == "" { // Try reading from file
 			serviceIdFile, _ := cmd.Flags().GetString("service-id-file")
 			content, err := os.ReadFile(serviceIdFile)
@@ -64,132 +89,152 @@ var iamLoginCmd = &cobra.Command{
 			}
 		}
 
-		if (clientID == "" || clientSecret == "") && serviceID == "" {
-			fmt.Printf("this feature only works with official binaries.\n")
-			return
+		// Browser logins authorize hsappclient and redeem the code using the hs client,
+		// a custom client is used for both and for the session afterwards
+		authClientID := "hsappclient"
+		tokenClientID, tokenClientSecret := clientID, clientSecret
+		customClientID, _ := cmd.Flags().GetString("client-id")
+		customClientSecret := ""
+		if customClientID != "" {
+			customClientSecret = os.Getenv("HS_IAM_CLIENT_SECRET")
+			authClientID = customClientID
+			tokenClientID, tokenClientSecret = customClientID, customClientSecret
+		}
+		if (clientID == "" || clientSecret == "") && serviceID == "" && customClientID == "" {
+			return fmt.Errorf("this feature only works with official binaries, or specify your own OAuth2 client using --client-id")
 		}
 		// IAM
 		iamClient, err := iam.NewClient(http.DefaultClient, &iam.Config{
 			Region:         region,
 			Environment:    environment,
-			OAuth2ClientID: clientID,
-			OAuth2Secret:   clientSecret,
+			OAuth2ClientID: tokenClientID,
+			OAuth2Secret:   tokenClientSecret,
 		})
 		if err != nil {
-			fmt.Printf("error initializing IAM client: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error initializing IAM client: %w", err)
 		}
 		if serviceID != "" { // Service Identity flow
-			privateKeyFile, _ := cmd.Flags().GetString("private-key-file")
-			key, err := os.ReadFile(privateKeyFile)
-			if err != nil {
-				fmt.Printf("error reading private key: %v\n", err)
-				os.Exit(1)
+			encodedKey := ""
+			if serviceKey != nil {
+				// Keep the identity so the session can be renewed without a refresh token
+				encodedKey, err = serviceKey.encode()
+				if err != nil {
+					return err
+				}
+			} else {
+				privateKeyFile, _ := cmd.Flags().GetString("private-key-file")
+				key, err := os.ReadFile(privateKeyFile)
+				if err != nil {
+					return fmt.Errorf("error reading private key: %w", err)
+				}
+				serviceKey = &Key{ID: serviceID, PrivateKey: string(key)}
 			}
 			err = iamClient.ServiceLogin(iam.Service{
-				ServiceID:  serviceID,
-				PrivateKey: string(key),
+				ServiceID:  serviceKey.ID,
+				PrivateKey: serviceKey.PrivateKey,
 			})
 			if err != nil {
-				fmt.Printf("error logging in: %v\n", err)
-				os.Exit(1)
+				return authRequired("error logging in: %w", err)
 			}
-			if clientID != "" {
+			currentWorkspace.IAMAccessTokenExpires = iamClient.Expires()
+			if iamClient.HasOAuth2Credentials() {
 				introspect, _, err := iamClient.Introspect()
 				if err != nil {
-					fmt.Printf("error performing introspect: %v\n", err)
-					return
+					return fmt.Errorf("error performing introspect: %w", err)
 				}
 				currentWorkspace.IAMUserUUID = introspect.Sub
 				currentWorkspace.IAMAccessTokenExpires = introspect.Expires
 			}
 			token, _ := iamClient.Token()
 			currentWorkspace.IAMAccessToken = token
+			currentWorkspace.IAMRefreshToken = ""
 			currentWorkspace.IAMIDToken = iamClient.IDToken()
+			currentWorkspace.IAMServiceKey = encodedKey
+			currentWorkspace.IAMClientID = customClientID
+			currentWorkspace.IAMClientSecret = customClientSecret
 			currentWorkspace.IAMRegion = region
 			currentWorkspace.IAMEnvironment = environment
 			if err := currentWorkspace.save(); err != nil {
-				fmt.Printf("failed to save workspace: %v\n", err)
-			}
-			if jsonOut {
-				data, _ := json.Marshal(tokenOutput{token})
-				fmt.Printf("%s\n", string(data))
+				return fmt.Errorf("failed to save workspace: %w", err)
 			}
-			return
-		}
-		e := echo.New()
-		e.HideBanner = true
-		redirectURI := "http://localhost:35444/callback"
-		loginSuccess := false
-		e.GET("/callback", func(c echo.Context) error {
-			code := c.QueryParam("code")
-			err := iamClient.CodeLogin(code, redirectURI)
-			if err != nil {
-				_ = c.HTML(http.StatusForbidden, "<html><body>Login failed</body></html>")
-				go func() {
-					time.Sleep(1 * time.Second)
-					_ = e.Shutdown(context.Background())
-				}()
-				return err
+			if !tableOutput() {
+				return printObject(tokenOutput{token})
 			}
-			_ = c.HTML(http.StatusOK, "<html><body>You are now logged in! Feel free to close this window...</body></html>")
-			loginSuccess = true
-			go func() {
-				time.Sleep(2 * time.Second)
-				_ = e.Shutdown(context.Background())
-			}()
 			return nil
-		})
-		baseIAMURL := iamClient.BaseIAMURL().String()
-		fmt.Printf("login using your browser to login...\n")
-		err = browser.OpenURL(baseIAMURL + "/authorize/oauth2/authorize?response_type=code&client_id=hsappclient&redirect_uri=http://localhost:35444/callback")
+		}
+		noBrowser, _ := cmd.Flags().GetBool("no-browser")
+		device, _ := cmd.Flags().GetBool("device")
+		callbackPort, _ := cmd.Flags().GetInt("callback-port")
+		flow, err := newLoginFlow(iamClient, authClientID, tokenClientID, tokenClientSecret)
 		if err != nil {
-			fmt.Printf("failed to open browser login: %v\n", err)
-			return
+			return err
 		}
-		done := make(chan bool)
-		go func(done chan bool) {
-			select {
-			case <-done:
-				return
-			case <-time.After(5 * time.Minute):
-				fmt.Printf("timed out waiting for login. Exiting ...\n")
-				_ = e.Shutdown(context.Background())
-			}
-		}(done)
-		_ = e.Start(":35444")
-		if !loginSuccess {
-			fmt.Printf("login failed. Please try again ...\n")
-			return
+		switch {
+		case device:
+			err = deviceLogin(cmd.Context(), iamClient, tokenClientID, tokenClientSecret, os.Stderr)
+		case noBrowser:
+			flow.redirectURI = callbackURI(callbackPort)
+			err = pasteLogin(cmd.Context(), flow, os.Stdin)
+		default:
+			// The hs client only accepts callbacks on the default port
+			fallback := customClientID != "" && !cmd.Flags().Changed("callback-port")
+			err = browserLogin(cmd.Context(), flow, callbackPort, fallback)
+		}
+		if err != nil {
+			return authRequired("login failed: %w", err)
 		}
 
 		introspect, _, err := iamClient.Introspect()
 		if err != nil {
-			fmt.Printf("error performing introspect: %v\n", err)
-			return
+			return fmt.Errorf("error performing introspect: %w", err)
 		}
 		fmt.Printf("logged in as: %s\n", introspect.Username)
 		token, _ := iamClient.Token()
 		currentWorkspace.IAMAccessToken = token
 		currentWorkspace.IAMRefreshToken = iamClient.RefreshToken()
 		currentWorkspace.IAMIDToken = iamClient.IDToken()
+		currentWorkspace.IAMServiceKey = ""
+		currentWorkspace.IAMClientID = customClientID
+		currentWorkspace.IAMClientSecret = customClientSecret
 		currentWorkspace.IAMUserUUID = introspect.Sub
 		currentWorkspace.IAMRegion = region
 		currentWorkspace.IAMEnvironment = environment
 		currentWorkspace.IAMAccessTokenExpires = introspect.Expires
-		if jsonOut {
-			data, _ := json.Marshal(tokenOutput{token})
-			fmt.Printf("%s\n", string(data))
-		}
 		if err := currentWorkspace.save(); err != nil {
-			fmt.Printf("failed to save workspace: %v\n", err)
+			return fmt.Errorf("failed to save workspace: %w", err)
 		}
+		if !tableOutput() {
+			return printObject(tokenOutput{token})
+		}
+		return nil
 	},
 }
 
+// loginServiceKey returns the key given using --key-file or HS_SERVICE_KEY, if any
+func loginServiceKey(cmd *cobra.Command) (*Key, error) {
+	keyFile, _ := cmd.Flags().GetString("key-file")
+	data, err := readKeyData(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	key, err := decodeKey(data)
+	if err != nil {
+		return nil, invalidInput("%v", err)
+	}
+	return key, nil
+}
+
 func init() {
 	iamCmd.AddCommand(iamLoginCmd)
+	iamLoginCmd.Flags().Bool("no-browser", false, "Print the login URL and paste the redirect URL instead of using a local browser")
+	iamLoginCmd.Flags().Bool("device", false, "Login using the OAuth2 device authorization grant")
+	iamLoginCmd.Flags().Int("callback-port", defaultCallbackPort, "Local port for the login callback, 0 selects a free port")
+	iamLoginCmd.Flags().String("client-id", "", "OAuth2 client ID to login with, its secret is read from HS_IAM_CLIENT_SECRET if needed")
 	iamLoginCmd.Flags().String("service-id", "", "The service ID to use")
 	iamLoginCmd.Flags().String("service-id-file", "", "A file containing the service id")
 	iamLoginCmd.Flags().String("private-key-file", "", "A file containing the private key")
+	iamLoginCmd.Flags().String("key-file", "", "A key file created by hs iam keygen, defaults to the key in $HS_SERVICE_KEY")
 }
@@ -0,0 +1,375 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/browser"
+)
+
+// defaultCallbackPort is the port of the redirect URI registered for hsappclient
+const defaultCallbackPort = 35444
+
+// loginFlow holds the per login secrets of an authorization code flow with PKCE
+type loginFlow struct {
+	client            *iam.Client
+	authClientID      string
+	tokenClientID     string
+	tokenClientSecret string
+	redirectURI       string
+	state             string
+	verifier          string
+}
+
+func newLoginFlow(client *iam.Client, authClientID, tokenClientID, tokenClientSecret string) (*loginFlow, error) {
+	state, err := randomURLString(16)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomURLString(32)
+	if err != nil {
+		return nil, err
+	}
+	return &loginFlow{
+		client:            client,
+		authClientID:      authClientID,
+		tokenClientID:     tokenClientID,
+		tokenClientSecret: tokenClientSecret,
+		redirectURI:       callbackURI(defaultCallbackPort),
+		state:             state,
+		verifier:          verifier,
+	}, nil
+}
+
+func randomURLString(size int) (string, error) {
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func callbackURI(port int) string {
+	return "http://localhost:" + strconv.Itoa(port) + "/callback"
+}
+
+// codeChallenge is the S256 PKCE challenge of the flow verifier
+func (f *loginFlow) codeChallenge() string {
+	sum := sha256.Sum256([]byte(f.verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// authorizeURL returns the IAM authorization code flow URL
+func (f *loginFlow) authorizeURL() string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", f.authClientID)
+	query.Set("redirect_uri", f.redirectURI)
+	query.Set("state", f.state)
+	query.Set("code_challenge", f.codeChallenge())
+	query.Set("code_challenge_method", "S256")
+	return f.client.BaseIAMURL().String() + "authorize/oauth2/authorize?" + query.Encode()
+}
+
+// validState compares state to the flow state in constant time
+func (f *loginFlow) validState(state string) bool {
+	return subtle.ConstantTimeCompare([]byte(state), []byte(f.state)) == 1
+}
+
+// exchange redeems code for tokens, proving possession of the PKCE verifier
+func (f *loginFlow) exchange(ctx context.Context, code string) error {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", f.redirectURI)
+	form.Set("code_verifier", f.verifier)
+	if f.tokenClientSecret == "" {
+		form.Set("client_id", f.tokenClientID)
+	}
+	var token oauthTokenResponse
+	status, err := iamOAuthRequest(ctx, f.client, "authorize/oauth2/token", form, f.tokenClientID, f.tokenClientSecret, &token)
+	if err != nil {
+		return err
+	}
+	if token.Error != "" {
+		return fmt.Errorf("%s %s", token.Error, token.ErrorDescription)
+	}
+	if status != http.StatusOK || token.AccessToken == "" {
+		return fmt.Errorf("token request failed with status %d", status)
+	}
+	f.client.SetTokens(token.AccessToken, token.RefreshToken, token.IDToken,
+		time.Now().Add(time.Duration(token.ExpiresIn)*time.Second).Unix())
+	return nil
+}
+
+// callbackListener listens on port on the loopback interface. Port 0 selects a
+// free port, as does a busy port when fallback is set. Only set fallback for
+// clients accepting any loopback redirect URI. The redirect URI uses localhost,
+// which browsers may resolve to ::1, so the IPv6 loopback is served as well
+// when it is available.
+func callbackListener(port int, fallback bool) (net.Listener, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port))
+	if err != nil && fallback && port != 0 {
+		fmt.Fprintf(os.Stderr, "port %d is in use, selecting a free port for the login callback\n", port)
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if err != nil && port != 0 && !fallback {
+		return nil, fmt.Errorf("login callback port %d is not available, it is probably in use by another hs login: finish or stop it, or use --no-browser: %w", port, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen for the login callback: %w", err)
+	}
+	ipv6, err := net.Listen("tcp", "[::1]:"+strconv.Itoa(listener.Addr().(*net.TCPAddr).Port))
+	if err != nil {
+		return listener, nil
+	}
+	return newLoopbackListener(listener, ipv6), nil
+}
+
+// loopbackListener accepts connections from several listeners. Addr reports
+// the address of the first one.
+type loopbackListener struct {
+	listeners []net.Listener
+	conns     chan net.Conn
+	errs      chan error
+	closed    chan struct{}
+	once      sync.Once
+}
+
+func newLoopbackListener(listeners ...net.Listener) *loopbackListener {
+	l := &loopbackListener{
+		listeners: listeners,
+		conns:     make(chan net.Conn),
+		errs:      make(chan error, len(listeners)),
+		closed:    make(chan struct{}),
+	}
+	for _, listener := range listeners {
+		go l.accept(listener)
+	}
+	return l
+}
+
+func (l *loopbackListener) accept(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			l.errs <- err
+			return
+		}
+		select {
+		case l.conns <- conn:
+		case <-l.closed:
+			_ = conn.Close()
+			return
+		}
+	}
+}
+
+func (l *loopbackListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	case err := <-l.errs:
+		return nil, err
+	}
+}
+
+func (l *loopbackListener) Close() error {
+	var err error
+	l.once.Do(func() {
+		close(l.closed)
+		for _, listener := range l.listeners {
+			if e := listener.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	})
+	return err
+}
+
+func (l *loopbackListener) Addr() net.Addr {
+	return l.listeners[0].Addr()
+}
+
+var loginPageTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>hs login</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; background: #f5f6f8; color: #1d2129; display: flex; align-items: center; justify-content: center; height: 100vh; margin: 0; }
+.card { background: #fff; border-radius: 8px; box-shadow: 0 2px 12px rgba(0,0,0,.1); padding: 2em 3em; max-width: 32em; text-align: center; }
+h1 { font-size: 1.4em; color: {{if .Success}}#1a7f37{{else}}#cf222e{{end}}; }
+p { line-height: 1.5; }
+</style>
+</head>
+<body>
+<div class="card">
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+</div>
+</body>
+</html>
+`))
+
+// loginPage renders the page the browser shows after the login callback
+func loginPage(success bool, title, message string) string {
+	var page strings.Builder
+	_ = loginPageTemplate.Execute(&page, struct {
+		Success        bool
+		Title, Message string
+	}{success, title, message})
+	return page.String()
+}
+
+// browserLogin opens the authorize URL in the browser and completes the login
+// from the redirect to a local callback server. When no browser can be opened
+// it falls back to pasteLogin.
+func browserLogin(ctx context.Context, flow *loginFlow, port int, fallback bool) error {
+	listener, err := callbackListener(port, fallback)
+	if err != nil {
+		return err
+	}
+	flow.redirectURI = callbackURI(listener.Addr().(*net.TCPAddr).Port)
+
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.Listener = listener
+	result := make(chan error, 1)
+	finish := func(err error) {
+		select {
+		case result <- err:
+		default:
+		}
+	}
+	e.GET("/callback", func(c echo.Context) error {
+		if !flow.validState(c.QueryParam("state")) {
+			// Ignore requests not belonging to this login, keep waiting for the real one
+			return c.HTML(http.StatusBadRequest, loginPage(false, "Login failed",
+				"This login request does not belong to the running hs login, please start a new login."))
+		}
+		if e := c.QueryParam("error"); e != "" {
+			finish(fmt.Errorf("%s %s", e, c.QueryParam("error_description")))
+			return c.HTML(http.StatusForbidden, loginPage(false, "Login failed",
+				fmt.Sprintf("IAM reported: %s. Close this window and try again.", e)))
+		}
+		if err := flow.exchange(c.Request().Context(), c.QueryParam("code")); err != nil {
+			finish(err)
+			return c.HTML(http.StatusForbidden, loginPage(false, "Login failed",
+				"The login could not be completed. Close this window and check the terminal for details."))
+		}
+		finish(nil)
+		return c.HTML(http.StatusOK, loginPage(true, "You are now logged in",
+			"You can close this window and return to the terminal."))
+	})
+	go func() {
+		if err := e.Start(""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			finish(err)
+		}
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = e.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "login using your browser to login...\n")
+	if err := browser.OpenURL(flow.authorizeURL()); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to open a browser: %v\n", err)
+		return pasteLogin(ctx, flow, os.Stdin)
+	}
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(5 * time.Minute):
+		return fmt.Errorf("timed out waiting for login")
+	}
+}
+
+// pasteLogin prints the authorize URL and completes the login using the
+// redirect URL or code the user pastes, for machines without a browser
+func pasteLogin(ctx context.Context, flow *loginFlow, in io.Reader) error {
+	fmt.Fprintf(os.Stderr, "open the following URL in a browser on any machine and login:\n\n  %s\n\n", flow.authorizeURL())
+	fmt.Fprintf(os.Stderr, "after logging in the browser is redirected to %s, which fails to load.\n", flow.redirectURI)
+	fmt.Fprintf(os.Stderr, "Paste the full URL from the address bar, or just the code parameter: ")
+	input, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && input == "" {
+		return fmt.Errorf("no code entered: %w", err)
+	}
+	code, state, err := codeFromInput(input)
+	if err != nil {
+		return err
+	}
+	if state != "" && !flow.validState(state) {
+		return fmt.Errorf("the pasted URL does not belong to this login, please use the URL printed above")
+	}
+	return flow.exchange(ctx, code)
+}
+
+// codeFromInput extracts the authorization code and state from a pasted redirect URL or code
+func codeFromInput(input string) (string, string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", "", invalidInput("no code entered")
+	}
+	if !strings.Contains(input, "://") && !strings.Contains(input, "?") {
+		return input, "", nil
+	}
+	u, err := url.Parse(input)
+	if err != nil {
+		return "", "", invalidInput("unable to parse the pasted URL: %v", err)
+	}
+	query := u.Query()
+	if e := query.Get("error"); e != "" {
+		return "", "", fmt.Errorf("login failed: %s %s", e, query.Get("error_description"))
+	}
+	code := query.Get("code")
+	if code == "" {
+		return "", "", invalidInput("the pasted URL does not contain a code parameter")
+	}
+	return code, query.Get("state"), nil
+}
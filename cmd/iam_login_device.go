@@ -0,0 +1,155 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dip-software/go-dip-api/iam"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// deviceAuthorization is the response of the OAuth2 device authorization endpoint (RFC 8628)
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+type oauthTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	IDToken          string `json:"id_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// deviceLogin logs in using the OAuth2 device authorization grant. The user
+// code and verification URL are written to out, the token endpoint is polled
+// until the login is approved, denied or expires.
+func deviceLogin(ctx context.Context, client *iam.Client, oauthClientID, oauthSecret string, out io.Writer) error {
+	var auth deviceAuthorization
+	form := url.Values{}
+	form.Set("client_id", oauthClientID)
+	status, err := iamOAuthRequest(ctx, client, "authorize/oauth2/device_authorization", form, oauthClientID, oauthSecret, &auth)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound || status == http.StatusBadRequest || status == http.StatusUnauthorized {
+		return fmt.Errorf("the IAM client does not allow device authorization (status %d), use --no-browser instead", status)
+	}
+	if status != http.StatusOK || auth.DeviceCode == "" {
+		return fmt.Errorf("device authorization failed with status %d", status)
+	}
+
+	fmt.Fprintf(out, "to login, open %s and enter the code: %s\n", auth.VerificationURI, auth.UserCode)
+	if auth.VerificationURIComplete != "" {
+		fmt.Fprintf(out, "or open %s\n", auth.VerificationURIComplete)
+	}
+	interval := time.Duration(auth.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	expiresIn := time.Duration(auth.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = 10 * time.Minute
+	}
+	deadline := time.Now().Add(expiresIn)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the device code expired, please try again")
+		}
+		form := url.Values{}
+		form.Set("grant_type", deviceCodeGrantType)
+		form.Set("device_code", auth.DeviceCode)
+		form.Set("client_id", oauthClientID)
+		var token oauthTokenResponse
+		status, err := iamOAuthRequest(ctx, client, "authorize/oauth2/token", form, oauthClientID, oauthSecret, &token)
+		if err != nil {
+			return err
+		}
+		switch token.Error {
+		case "":
+			if status != http.StatusOK || token.AccessToken == "" {
+				return fmt.Errorf("token request failed with status %d", status)
+			}
+			client.SetTokens(token.AccessToken, token.RefreshToken, token.IDToken,
+				time.Now().Add(time.Duration(token.ExpiresIn)*time.Second).Unix())
+			return nil
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += 5 * time.Second
+			continue
+		case "access_denied":
+			return fmt.Errorf("login was denied")
+		case "expired_token":
+			return fmt.Errorf("the device code expired, please try again")
+		default:
+			return fmt.Errorf("login failed: %s %s", token.Error, token.ErrorDescription)
+		}
+	}
+}
+
+// iamOAuthRequest posts form to an IAM OAuth2 endpoint and decodes the JSON
+// response into out, also for error statuses as OAuth2 reports errors in the body
+func iamOAuthRequest(ctx context.Context, client *iam.Client, path string, form url.Values, user, password string, out interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.BaseIAMURL().String()+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.SetBasicAuth(user, password)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Api-Version", "2")
+	resp, err := client.HttpClient().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, out)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dip-software/go-dip-api/iam"
+)
+
+func TestCodeFromInput(t *testing.T) {
+	for input, expected := range map[string][2]string{
+		"abc123\n": {"abc123", ""},
+		"http://localhost:35444/callback?code=abc123&state=xyz": {"abc123", "xyz"},
+		"  http://localhost:35444/callback?code=abc%2F123  ":    {"abc/123", ""},
+	} {
+		code, state, err := codeFromInput(input)
+		if err != nil {
+			t.Errorf("%q: %v", input, err)
+			continue
+		}
+		if code != expected[0] || state != expected[1] {
+			t.Errorf("%q: expected %q, got %q %q", input, expected, code, state)
+		}
+	}
+	for _, input := range []string{"", "http://localhost:35444/callback?state=xyz", "http://localhost:35444/callback?error=access_denied"} {
+		if _, _, err := codeFromInput(input); err == nil {
+			t.Errorf("%q: expected error", input)
+		}
+	}
+}
+
+func TestDeviceLogin(t *testing.T) {
+	var polls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize/oauth2/device_authorization", func(w http.ResponseWriter, r *http.Request) {
+		if user, _, _ := r.BasicAuth(); user != "client" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(deviceAuthorization{
+			DeviceCode:      "device",
+			UserCode:        "ABCD-EFGH",
+			VerificationURI: "https://iam.example.com/device",
+			ExpiresIn:       60,
+			Interval:        1,
+		})
+	})
+	mux.HandleFunc("/authorize/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != deviceCodeGrantType || r.Form.Get("device_code") != "device" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(oauthTokenResponse{Error: "invalid_grant"})
+			return
+		}
+		if atomic.AddInt32(&polls, 1) < 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(oauthTokenResponse{Error: "authorization_pending"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(oauthTokenResponse{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 1800})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client, err := iam.NewClient(http.DefaultClient, &iam.Config{
+		OAuth2ClientID: "client",
+		OAuth2Secret:   "secret",
+		IAMURL:         server.URL,
+		IDMURL:         server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deviceLogin(context.Background(), client, "client", "secret", io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if token, _ := client.Token(); token != "access" || client.RefreshToken() != "refresh" {
+		t.Errorf("expected tokens to be set, got %q %q", token, client.RefreshToken())
+	}
+	if err := deviceLogin(context.Background(), client, "other", "secret", io.Discard); err == nil {
+		t.Error("expected error when device authorization is not allowed")
+	}
+}
+
+func TestLoginFlowPKCE(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("code_verifier") == "" || r.Form.Get("code") != "abc" || r.Form.Get("client_id") != "public" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(oauthTokenResponse{Error: "invalid_grant"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(oauthTokenResponse{AccessToken: "access", ExpiresIn: 60})
+	}))
+	defer server.Close()
+	client, err := iam.NewClient(http.DefaultClient, &iam.Config{
+		IAMURL: server.URL,
+		IDMURL: server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flow, err := newLoginFlow(client, "hsappclient", "public", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := newLoginFlow(client, "hsappclient", "public", "")
+	if flow.state == other.state || flow.verifier == other.verifier {
+		t.Error("expected a random state and verifier per login")
+	}
+	authorize, err := url.Parse(flow.authorizeURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := authorize.Query()
+	sum := sha256.Sum256([]byte(flow.verifier))
+	if query.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(sum[:]) || query.Get("code_challenge_method") != "S256" {
+		t.Errorf("unexpected challenge in %s", authorize)
+	}
+	if query.Get("state") != flow.state || query.Get("client_id") != "hsappclient" {
+		t.Errorf("unexpected state or client in %s", authorize)
+	}
+	if !flow.validState(flow.state) || flow.validState(other.state) || flow.validState("") {
+		t.Error("state validation failed")
+	}
+	if err := flow.exchange(context.Background(), "wrong"); err == nil {
+		t.Error("expected error for an invalid code")
+	}
+	if err := flow.exchange(context.Background(), "abc"); err != nil {
+		t.Fatalf("exchange failed: %v", err)
+	}
+	if token, _ := client.Token(); token != "access" {
+		t.Errorf("expected token to be set, got %q", token)
+	}
+}
+
+func TestLoginPage(t *testing.T) {
+	page := loginPage(false, "Login failed", "IAM reported: <script>")
+	if strings.Contains(page, "<script>") {
+		t.Error("expected message to be escaped")
+	}
+	if !strings.Contains(page, "Login failed") {
+		t.Error("expected title in page")
+	}
+}
+
+func TestCustomClientSessionUsesThatClient(t *testing.T) {
+	var refreshed, introspected int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "custom" || pass != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		atomic.AddInt32(&refreshed, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(oauthTokenResponse{AccessToken: "new", RefreshToken: "refresh2", ExpiresIn: 60})
+	})
+	mux.HandleFunc("/authorize/oauth2/introspect", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "custom" || pass != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		atomic.AddInt32(&introspected, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"active":true,"sub":"user-uuid"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ws := &workspaceConfig{IAMClientID: "custom", IAMClientSecret: "s3cret"}
+	if _, ok := ws.secretFields()["IAMClientSecret"]; !ok {
+		t.Error("expected the client secret to be kept in the secret store")
+	}
+	config := ws.iamConfig()
+	config.IAMURL, config.IDMURL = server.URL, server.URL
+	client, err := iam.NewClient(http.DefaultClient, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetTokens("old", "refresh", "", 1)
+	if err := client.TokenRefresh(); err != nil {
+		t.Fatalf("refresh using the login client failed: %v", err)
+	}
+	if introspect, _, err := client.Introspect(); err != nil || introspect.Sub != "user-uuid" {
+		t.Fatalf("introspect using the login client failed: %v", err)
+	}
+	if atomic.LoadInt32(&refreshed) != 1 || atomic.LoadInt32(&introspected) != 1 {
+		t.Errorf("expected one refresh and introspect, got %d and %d", refreshed, introspected)
+	}
+
+	if config := (&workspaceConfig{}).iamConfig(); config.OAuth2ClientID != clientID || config.OAuth2Secret != clientSecret {
+		t.Error("expected sessions without a custom client to use the hs client")
+	}
+}
+
+func TestCallbackListenerPortInUse(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+	port := busy.Addr().(*net.TCPAddr).Port
+
+	if _, err := callbackListener(port, false); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Errorf("expected a port in use error without fallback, got %v", err)
+	}
+	listener, err := callbackListener(port, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	if listener.Addr().(*net.TCPAddr).Port == port {
+		t.Error("expected a free port to be selected")
+	}
+}
+
+func TestCallbackListenerServesLocalhost(t *testing.T) {
+	listener, err := callbackListener(0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	addresses := []string{"127.0.0.1:" + port}
+	if _, ok := listener.(*loopbackListener); ok {
+		addresses = append(addresses, "[::1]:"+port)
+	}
+	for _, address := range addresses {
+		conn, err := net.Dial("tcp", address)
+		if err != nil {
+			t.Fatalf("%s: %v", address, err)
+		}
+		accepted, err := listener.Accept()
+		if err != nil {
+			t.Fatalf("%s: %v", address, err)
+		}
+		_ = accepted.Close()
+		_ = conn.Close()
+	}
+}
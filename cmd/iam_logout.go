@@ -18,6 +18,7 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -32,30 +33,36 @@ and usage of using your command. For example:
 Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		iamClient, err := getIAMClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iamClient, err := newIAMClient()
 		if err != nil {
-			fmt.Printf("error initalizing IAM client: %v\n", err)
-			return
+			return fmt.Errorf("error initalizing IAM client: %w", err)
 		}
 
 		// Check the error return values of RevokeRefreshAccessToken and RevokeAccessToken
 		err = iamClient.RevokeRefreshAccessToken()
 		if err != nil {
-			fmt.Printf("Error revoking refresh access token: %v\n", err)
+			fmt.Fprintf(os.Stderr, "warning: error revoking refresh access token: %v\n", err)
 		}
 
 		err = iamClient.RevokeAccessToken()
 		if err != nil {
-			fmt.Printf("Error revoking access token: %v\n", err)
+			fmt.Fprintf(os.Stderr, "warning: error revoking access token: %v\n", err)
 		}
 
 		err = iamClient.EndSession()
-		if err == io.EOF {
-			fmt.Printf("Session ended\n")
-		} else {
-			fmt.Printf("Eror: %v\n", err)
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("error ending session: %w", err)
 		}
+		if currentWorkspace.IAMServiceKey != "" {
+			// Stop logging in again automatically using the stored service identity
+			currentWorkspace.IAMServiceKey = ""
+			if err := currentWorkspace.save(); err != nil {
+				return fmt.Errorf("failed to save workspace: %w", err)
+			}
+		}
+		fmt.Printf("Session ended\n")
+		return nil
 	},
 }
 
@@ -22,17 +22,31 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/dip-software/go-dip-api/iam"
 	"github.com/spf13/cobra"
 )
 
+// orgTreeWorkers is the default number of concurrent requests when walking an organization tree
+const orgTreeWorkers = 5
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
 // iamOrgsCmd represents the orgs command
 var iamOrgsCmd = &cobra.Command{
 	Use:     "orgs",
 	Aliases: []string{"o", "org"},
 	Short:   "Manage IAM organizations",
 	Long:    `Manage IAM organizations.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -49,3 +63,117 @@ func init() {
 	// is called directly, e.g.:
 	// iamOrgsCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
+
+// resolveOrg finds an organization by ID or name, an empty ref is the selected organization
+func resolveOrg(client *iam.Client, ref string) (*iam.Organization, error) {
+	if ref == "" {
+		if currentWorkspace.IAMSelectedOrg == "" {
+			return nil, errNoOrgSelected
+		}
+		ref = currentWorkspace.IAMSelectedOrg
+	}
+	if uuidPattern.MatchString(ref) {
+		org, _, err := client.Organizations.GetOrganizationByID(ref)
+		if err != nil {
+			if classifyError(err) == kindNotFound {
+				return nil, notFound("organization '%s' not found", ref)
+			}
+			return nil, fmt.Errorf("error retrieving organization '%s': %w", ref, err)
+		}
+		return org, nil
+	}
+	org, _, err := client.Organizations.GetOrganization(iam.FilterNameEq(ref))
+	if err != nil {
+		if classifyError(err) == kindNotFound {
+			return nil, notFound("organization '%s' not found", ref)
+		}
+		return nil, fmt.Errorf("error retrieving organization '%s': %w", ref, err)
+	}
+	return org, nil
+}
+
+// listChildOrgs returns the direct sub organizations of parentID, sorted by name
+func listChildOrgs(client *iam.Client, parentID string) ([]iam.Organization, error) {
+	const count = 100
+	children := make([]iam.Organization, 0)
+	for start := 1; ; start += count {
+		query := url.Values{}
+		query.Set("filter", `parent.value eq "`+parentID+`"`)
+		query.Set("attributes", "id,name,displayName,description,type,externalId,parent")
+		query.Set("startIndex", strconv.Itoa(start))
+		query.Set("count", strconv.Itoa(count))
+		var page struct {
+			TotalResults int                `json:"totalResults"`
+			Resources    []iam.Organization `json:"Resources"`
+		}
+		if err := iamIDMRequest(client, "GET", "authorize/scim/v2/Organizations?"+query.Encode(), "2", nil, &page); err != nil {
+			return nil, fmt.Errorf("error listing sub organizations of %s: %w", parentID, err)
+		}
+		children = append(children, page.Resources...)
+		if len(page.Resources) < count || len(children) >= page.TotalResults {
+			break
+		}
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Name < children[j].Name
+	})
+	return children, nil
+}
+
+type orgNode struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name"`
+	Type     string     `json:"type,omitempty"`
+	Children []*orgNode `json:"children,omitempty"`
+}
+
+// walkOrgTree retrieves the hierarchy below root, descending at most maxDepth
+// levels (0 is unlimited) with at most workers concurrent requests.
+// The first error cancels the walk.
+func walkOrgTree(ctx context.Context, client *iam.Client, root *orgNode, maxDepth, workers int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var walkErr error
+	fail := func(err error) {
+		once.Do(func() {
+			walkErr = err
+			cancel()
+		})
+	}
+
+	var walk func(node *orgNode, depth int)
+	walk = func(node *orgNode, depth int) {
+		defer wg.Done()
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		children, err := listChildOrgs(client, node.ID)
+		<-sem
+		if err != nil {
+			fail(err)
+			return
+		}
+		node.Children = make([]*orgNode, 0, len(children))
+		for _, child := range children {
+			childNode := &orgNode{ID: child.ID, Name: child.Name, Type: child.Type}
+			node.Children = append(node.Children, childNode)
+			if maxDepth == 0 || depth < maxDepth {
+				wg.Add(1)
+				go walk(childNode, depth+1)
+			}
+		}
+	}
+	wg.Add(1)
+	go walk(root, 1)
+	wg.Wait()
+	if walkErr != nil {
+		return walkErr
+	}
+	return ctx.Err()
+}
@@ -24,20 +24,54 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/dip-software/go-dip-api/iam"
 	"github.com/spf13/cobra"
 )
 
 // iamOrgsCreateCmd represents the create command
 var iamOrgsCreateCmd = &cobra.Command{
-	Use:   "create",
+	Use:   "create <name>",
 	Short: "Create an IAM sub ORG",
-	Long:  `Creates an IAM sub organization.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("create called")
+	Long: `Creates an IAM sub organization.
+
+The new organization is created below the selected organization unless
+--parent is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+		parentRef, _ := cmd.Flags().GetString("parent")
+		parent, err := resolveOrg(iamClient, parentRef)
+		if err != nil {
+			return err
+		}
+		org := iam.Organization{
+			Name:   args[0],
+			Parent: iam.Attribute{Value: parent.ID},
+		}
+		org.DisplayName, _ = cmd.Flags().GetString("display-name")
+		org.Description, _ = cmd.Flags().GetString("description")
+		org.Type, _ = cmd.Flags().GetString("type")
+		org.ExternalID, _ = cmd.Flags().GetString("external-id")
+		created, _, err := iamClient.Organizations.CreateOrganization(org)
+		if err != nil {
+			return fmt.Errorf("error creating organization '%s': %w", org.Name, err)
+		}
+		if created == nil {
+			return fmt.Errorf("organization not returned after create")
+		}
+		return printOutput(created, []string{"organization", "id", "parent"},
+			[][]interface{}{{created.Name, created.ID, parent.Name}})
 	},
 }
 
 func init() {
 	iamOrgsCmd.AddCommand(iamOrgsCreateCmd)
-
+	iamOrgsCreateCmd.Flags().String("parent", "", "Parent organization name or ID (default is the selected organization)")
+	iamOrgsCreateCmd.Flags().String("display-name", "", "Display name")
+	iamOrgsCreateCmd.Flags().String("description", "", "Description")
+	iamOrgsCreateCmd.Flags().String("type", "", "Organization type, e.g. Hospital")
+	iamOrgsCreateCmd.Flags().String("external-id", "", "External ID")
 }
@@ -0,0 +1,121 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+// iamOrgsDeleteCmd represents the delete command
+var iamOrgsDeleteCmd = &cobra.Command{
+	Use:     "delete <org>",
+	Aliases: []string{"rm"},
+	Short:   "Delete an IAM organization",
+	Long: `Deletes an organization, identified by name or ID, including all resources in it.
+
+IAM deletes organizations asynchronously, use --wait to wait until the delete
+has finished or --timeout passed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+		org, err := resolveOrg(iamClient, args[0])
+		if err != nil {
+			return err
+		}
+		yes, _ := cmd.Flags().GetBool("yes")
+		if err := confirm(fmt.Sprintf("Delete organization '%s' (%s) and everything in it", org.Name, org.ID), yes); err != nil {
+			return err
+		}
+		ok, resp, err := iamClient.Organizations.DeleteOrganization(*org)
+		if err := actionOK(ok, resp, err); err != nil {
+			return fmt.Errorf("error deleting organization '%s': %w", org.Name, err)
+		}
+		if org.ID == currentWorkspace.IAMSelectedOrg {
+			currentWorkspace.IAMSelectedOrg = ""
+			currentWorkspace.IAMSelectedOrgName = ""
+			if err := currentWorkspace.save(); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "deleted the selected organization, select another one using: hs iam orgs select\n")
+		}
+		status := "IN_PROGRESS"
+		if wait, _ := cmd.Flags().GetBool("wait"); wait {
+			timeout, _ := cmd.Flags().GetDuration("timeout")
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			status, err = waitOrgDeleted(ctx, iamClient, org.ID, orgDeletePollInterval)
+			if err != nil {
+				return fmt.Errorf("waiting for delete of organization '%s': %w", org.Name, err)
+			}
+			if status == "FAILED" {
+				return fmt.Errorf("deleting organization '%s' failed", org.Name)
+			}
+		}
+		return printOutput(map[string]string{"id": org.ID, "name": org.Name, "status": status},
+			[]string{"organization", "id", "status"}, [][]interface{}{{org.Name, org.ID, status}})
+	},
+}
+
+// orgDeletePollInterval is the delay between delete status checks
+const orgDeletePollInterval = 5 * time.Second
+
+// waitOrgDeleted polls the delete status of organization id until it is no
+// longer queued or in progress and returns the final status. It gives up
+// when ctx is done.
+func waitOrgDeleted(ctx context.Context, client *iam.Client, id string, interval time.Duration) (string, error) {
+	status := "IN_PROGRESS"
+	for status == "IN_PROGRESS" || status == "QUEUED" {
+		select {
+		case <-ctx.Done():
+			return status, fmt.Errorf("delete still %s: %w", status, ctx.Err())
+		case <-time.After(interval):
+		}
+		result, _, err := client.Organizations.DeleteStatus(id)
+		if err != nil {
+			return status, fmt.Errorf("error retrieving delete status: %w", err)
+		}
+		if result == nil {
+			return status, fmt.Errorf("error retrieving delete status: empty response")
+		}
+		status = result.Status
+	}
+	return status, nil
+}
+
+func init() {
+	iamOrgsCmd.AddCommand(iamOrgsDeleteCmd)
+	iamOrgsDeleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	iamOrgsDeleteCmd.Flags().Bool("wait", false, "Wait until the delete has finished")
+	iamOrgsDeleteCmd.Flags().Duration("timeout", 30*time.Minute, "Maximum time to wait for the delete with --wait")
+}
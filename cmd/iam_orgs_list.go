@@ -22,10 +22,8 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
-	"github.com/cheynewallace/tabby"
 	"github.com/spf13/cobra"
 )
 
@@ -35,30 +33,21 @@ var iamOrgsListCmd = &cobra.Command{
 	Aliases: []string{"l", "ls"},
 	Short:   "List IAM organizations",
 	Long:    `Lists IAM organizations you have access to.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		iamClient, err := getIAMClient(cmd)
 		if err != nil {
-			fmt.Printf("error initalizing IAM client: %v\n", err)
-			return
+			return fmt.Errorf("error initalizing IAM client: %w", err)
 		}
 		introspect, _, err := iamClient.Introspect()
 		if err != nil {
-			fmt.Printf("error performing IAM introspect: %v\n", err)
-			return
+			return fmt.Errorf("error performing IAM introspect: %w", err)
 		}
-		if jsonOut {
-			data, _ := json.Marshal(introspect.Organizations.OrganizationList)
-			fmt.Printf("%s\n", string(data))
-			return
-		}
-		t := tabby.New()
-		t.AddHeader("organization", "id")
+		rows := make([][]interface{}, 0)
 		for _, org := range introspect.Organizations.OrganizationList {
-			t.AddLine(org.OrganizationName,
-				org.OrganizationID)
+			rows = append(rows, []interface{}{org.OrganizationName,
+				org.OrganizationID})
 		}
-		t.Print()
-		_ = currentWorkspace.saveWithIAM(iamClient)
+		return printOutput(introspect.Organizations.OrganizationList, []string{"organization", "id"}, rows)
 	},
 }
 
@@ -23,9 +23,13 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"syscall"
 
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 type iamOrg struct {
@@ -42,24 +46,26 @@ var orgSelectTemplate = &promptui.SelectTemplates{
 
 // iamOrgsSelectCmd represents the select command
 var iamOrgsSelectCmd = &cobra.Command{
-	Use:     "select",
+	Use:     "select [name-or-id]",
 	Aliases: []string{"s"},
 	Short:   "Select active organization",
-	Long:    `Selects the active organization.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Selects the active organization.
+
+The organization can be given by ID, by name or by a prefix or fuzzy match of
+its name. When more than one organization matches the candidates are listed.
+Without an argument an interactive list is shown, type / to search it.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		iamClient, err := getIAMClient(cmd)
 		if err != nil {
-			fmt.Printf("error initalizing IAM client: %v\n", err)
-			return
+			return fmt.Errorf("error initalizing IAM client: %w", err)
 		}
 		introspect, _, err := iamClient.Introspect()
 		if err != nil {
-			fmt.Printf("error performing IAM introspect: %v\n", err)
-			return
+			return fmt.Errorf("error performing IAM introspect: %w", err)
 		}
 		if len(introspect.Organizations.OrganizationList) == 0 {
-			fmt.Printf("No organizations found\n")
-			return
+			return notFound("no organizations found")
 		}
 		orgs := make([]iamOrg, 0)
 		for _, o := range introspect.Organizations.OrganizationList {
@@ -68,24 +74,91 @@ var iamOrgsSelectCmd = &cobra.Command{
 				ID:   o.OrganizationID,
 			})
 		}
-		prompt := promptui.Select{
-			Label:     "Select active organization",
-			Items:     orgs,
-			HideHelp:  true,
-			Templates: orgSelectTemplate,
-			IsVimMode: false,
-			Stdout:    &bellSkipper{},
+		var selected iamOrg
+		if len(args) > 0 {
+			if selected, err = matchOrg(orgs, args[0]); err != nil {
+				return err
+			}
+		} else {
+			if !term.IsTerminal(int(syscall.Stdin)) {
+				return invalidInput("not running interactively, specify the organization name or ID")
+			}
+			prompt := promptui.Select{
+				Label:     "Select active organization",
+				Items:     orgs,
+				HideHelp:  true,
+				Templates: orgSelectTemplate,
+				IsVimMode: false,
+				Stdout:    &bellSkipper{},
+				Size:      10,
+				Searcher: func(input string, index int) bool {
+					return fuzzyMatch(orgs[index].Name, input) || strings.HasPrefix(orgs[index].ID, input)
+				},
+				StartInSearchMode: len(orgs) > 10,
+			}
+			i, _, err := prompt.Run()
+			if err != nil {
+				return err
+			}
+			selected = orgs[i]
 		}
-		i, _, err := prompt.Run()
-		if err != nil {
-			return
+		currentWorkspace.IAMSelectedOrg = selected.ID
+		currentWorkspace.IAMSelectedOrgName = selected.Name
+		if err := currentWorkspace.save(); err != nil {
+			return err
 		}
-		currentWorkspace.IAMSelectedOrg = orgs[i].ID
-		currentWorkspace.IAMSelectedOrgName = orgs[i].Name
-		_ = currentWorkspace.save()
+		fmt.Fprintf(os.Stderr, "selected organization %s (%s)\n", selected.Name, selected.ID)
+		return nil
 	},
 }
 
+// matchOrg finds the organization query refers to. An exact ID or name match
+// wins over a name prefix match, which wins over a fuzzy match. Multiple
+// matches at the same level are an error listing the candidates.
+func matchOrg(orgs []iamOrg, query string) (iamOrg, error) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	matchers := []func(o iamOrg) bool{
+		func(o iamOrg) bool { return strings.ToLower(o.ID) == q },
+		func(o iamOrg) bool { return strings.ToLower(o.Name) == q },
+		func(o iamOrg) bool { return strings.HasPrefix(strings.ToLower(o.Name), q) },
+		func(o iamOrg) bool { return fuzzyMatch(o.Name, q) },
+	}
+	for _, match := range matchers {
+		candidates := make([]iamOrg, 0)
+		for _, o := range orgs {
+			if match(o) {
+				candidates = append(candidates, o)
+			}
+		}
+		switch len(candidates) {
+		case 0:
+			continue
+		case 1:
+			return candidates[0], nil
+		}
+		list := make([]string, 0, len(candidates))
+		for _, o := range candidates {
+			list = append(list, fmt.Sprintf("  %s (%s)", o.Name, o.ID))
+		}
+		return iamOrg{}, invalidInput("'%s' matches %d organizations, use the name or ID of one of:\n%s",
+			query, len(candidates), strings.Join(list, "\n"))
+	}
+	return iamOrg{}, notFound("no organization matches '%s', use 'hs iam orgs list' to see your organizations", query)
+}
+
+// fuzzyMatch reports whether the characters of input appear in order in name, ignoring case
+func fuzzyMatch(name, input string) bool {
+	name = strings.ToLower(name)
+	for _, r := range strings.ToLower(strings.ReplaceAll(input, " ", "")) {
+		i := strings.IndexRune(name, r)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(string(r)):]
+	}
+	return true
+}
+
 func init() {
 	iamOrgsCmd.AddCommand(iamOrgsSelectCmd)
 
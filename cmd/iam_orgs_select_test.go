@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestMatchOrg(t *testing.T) {
+	orgs := []iamOrg{
+		{ID: "11111111-aaaa", Name: "Production"},
+		{ID: "22222222-bbbb", Name: "Prod"},
+		{ID: "33333333-cccc", Name: "Staging EU"},
+		{ID: "44444444-dddd", Name: "Staging US"},
+		{ID: "55555555-eeee", Name: "Development"},
+	}
+	for _, tc := range []struct {
+		query string
+		id    string
+	}{
+		{"33333333-CCCC", "33333333-cccc"},
+		{"prod", "22222222-bbbb"},
+		{"produ", "11111111-aaaa"},
+		{"dvlpmnt", "55555555-eeee"},
+		{"stg us", "44444444-dddd"},
+	} {
+		org, err := matchOrg(orgs, tc.query)
+		if err != nil {
+			t.Errorf("%s: %v", tc.query, err)
+			continue
+		}
+		if org.ID != tc.id {
+			t.Errorf("%s: expected %s, got %s", tc.query, tc.id, org.ID)
+		}
+	}
+	if _, err := matchOrg(orgs, "staging"); err == nil || classifyError(err) != kindInvalidInput {
+		t.Errorf("expected ambiguity error, got %v", err)
+	}
+	if _, err := matchOrg(orgs, "qa"); err == nil || classifyError(err) != kindNotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dip-software/go-dip-api/iam"
+)
+
+func TestWalkOrgTree(t *testing.T) {
+	hierarchy := map[string][]string{
+		"root": {"b", "a"},
+		"a":    {"a2", "a1"},
+		"a1":   {"a1x"},
+	}
+	parentFilter := regexp.MustCompile(`parent.value eq "(.*)"`)
+	var active, peak int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize/scim/v2/Organizations", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		parent := parentFilter.FindStringSubmatch(r.URL.Query().Get("filter"))[1]
+		resources := make([]iam.Organization, 0)
+		for _, child := range hierarchy[parent] {
+			resources = append(resources, iam.Organization{ID: child, Name: child})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"totalResults": len(resources),
+			"Resources":    resources,
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client, err := iam.NewClient(http.DefaultClient, &iam.Config{
+		OAuth2ClientID: "client",
+		OAuth2Secret:   "secret",
+		IAMURL:         server.URL,
+		IDMURL:         server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetTokens("token", "refresh", "", time.Now().Add(time.Hour).Unix())
+
+	root := &orgNode{ID: "root", Name: "root"}
+	if err := walkOrgTree(context.Background(), client, root, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(root.Children) != 2 || root.Children[0].Name != "a" || root.Children[1].Name != "b" {
+		t.Fatalf("unexpected children %+v", root.Children)
+	}
+	a := root.Children[0]
+	if len(a.Children) != 2 || len(a.Children[0].Children) != 1 || a.Children[0].Children[0].Name != "a1x" {
+		t.Errorf("unexpected subtree of a: %+v", a.Children)
+	}
+	if peak := atomic.LoadInt32(&peak); peak > 2 {
+		t.Errorf("expected at most 2 concurrent requests, got %d", peak)
+	}
+
+	root = &orgNode{ID: "root", Name: "root"}
+	if err := walkOrgTree(context.Background(), client, root, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(root.Children) != 2 || root.Children[0].Children != nil {
+		t.Errorf("expected depth 1 to stop below the direct children")
+	}
+}
+
+func TestWaitOrgDeleted(t *testing.T) {
+	var polls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize/scim/v2/Organizations/", func(w http.ResponseWriter, r *http.Request) {
+		status := "IN_PROGRESS"
+		if r.URL.Path == "/authorize/scim/v2/Organizations/done/deleteStatus" && atomic.AddInt32(&polls, 1) > 2 {
+			status = "SUCCESS"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(iam.OrganizationStatus{ID: "org", Status: status})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client, err := iam.NewClient(http.DefaultClient, &iam.Config{
+		OAuth2ClientID: "client",
+		OAuth2Secret:   "secret",
+		IAMURL:         server.URL,
+		IDMURL:         server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetTokens("token", "refresh", "", time.Now().Add(time.Hour).Unix())
+
+	status, err := waitOrgDeleted(context.Background(), client, "done", time.Millisecond)
+	if err != nil || status != "SUCCESS" {
+		t.Errorf("expected SUCCESS after 3 polls, got %s %v (%d polls)", status, err, atomic.LoadInt32(&polls))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	status, err = waitOrgDeleted(ctx, client, "stuck", time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) || status != "IN_PROGRESS" {
+		t.Errorf("expected the wait to time out, got %s %v", status, err)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+)
+
+// iamOrgsTreeCmd represents the tree command
+var iamOrgsTreeCmd = &cobra.Command{
+	Use:   "tree [org]",
+	Short: "Show the IAM organization hierarchy",
+	Long: `Shows the organization hierarchy below an organization, identified by
+name or ID. Without an argument the tree of the selected organization is shown.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		depth, _ := cmd.Flags().GetInt("depth")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		if depth < 0 {
+			return invalidInput("--depth must not be negative")
+		}
+		if concurrency < 1 {
+			return invalidInput("--concurrency must be at least 1")
+		}
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+		ref := ""
+		if len(args) > 0 {
+			ref = args[0]
+		}
+		org, err := resolveOrg(iamClient, ref)
+		if err != nil {
+			return err
+		}
+		root := &orgNode{ID: org.ID, Name: org.Name, Type: org.Type}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		if err := walkOrgTree(ctx, iamClient, root, depth, concurrency); err != nil {
+			return err
+		}
+		if !tableOutput() {
+			return printObject(root)
+		}
+		fmt.Printf("%s (%s)\n", root.Name, root.ID)
+		printOrgTree(root.Children, "")
+		return nil
+	},
+}
+
+func printOrgTree(nodes []*orgNode, indent string) {
+	for i, node := range nodes {
+		branch, next := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, next = "└── ", "    "
+		}
+		fmt.Printf("%s%s%s (%s)\n", indent, branch, node.Name, node.ID)
+		printOrgTree(node.Children, indent+next)
+	}
+}
+
+func init() {
+	iamOrgsCmd.AddCommand(iamOrgsTreeCmd)
+	iamOrgsTreeCmd.Flags().Int("depth", 0, "Maximum depth to descend, 0 is unlimited")
+	iamOrgsTreeCmd.Flags().Int("concurrency", orgTreeWorkers, "Maximum number of concurrent IAM requests")
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// iamOrgsUpdateCmd represents the update command
+var iamOrgsUpdateCmd = &cobra.Command{
+	Use:     "update [org]",
+	Aliases: []string{"u"},
+	Short:   "Update an IAM organization",
+	Long: `Updates an organization, identified by name or ID.
+
+Without an argument the selected organization is updated. Only the fields
+which are given are changed.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+		ref := ""
+		if len(args) > 0 {
+			ref = args[0]
+		}
+		org, err := resolveOrg(iamClient, ref)
+		if err != nil {
+			return err
+		}
+		fields := map[string]*string{
+			"name":         &org.Name,
+			"display-name": &org.DisplayName,
+			"description":  &org.Description,
+			"type":         &org.Type,
+			"external-id":  &org.ExternalID,
+		}
+		changed := false
+		for flag, field := range fields {
+			if cmd.Flags().Changed(flag) {
+				*field, _ = cmd.Flags().GetString(flag)
+				changed = true
+			}
+		}
+		if !changed {
+			return invalidInput("nothing to update, specify at least one field to change")
+		}
+		if org.Meta == nil {
+			return fmt.Errorf("organization '%s' has no version, unable to update", org.Name)
+		}
+		updated, _, err := iamClient.Organizations.UpdateOrganization(*org)
+		if err != nil {
+			return fmt.Errorf("error updating organization '%s': %w", org.Name, err)
+		}
+		if updated == nil {
+			return fmt.Errorf("organization not returned after update")
+		}
+		if org.ID == currentWorkspace.IAMSelectedOrg && updated.Name != "" && updated.Name != currentWorkspace.IAMSelectedOrgName {
+			currentWorkspace.IAMSelectedOrgName = updated.Name
+			if err := currentWorkspace.save(); err != nil {
+				return err
+			}
+		}
+		return printObject(updated)
+	},
+}
+
+func init() {
+	iamOrgsCmd.AddCommand(iamOrgsUpdateCmd)
+	iamOrgsUpdateCmd.Flags().String("name", "", "Name")
+	iamOrgsUpdateCmd.Flags().String("display-name", "", "Display name")
+	iamOrgsUpdateCmd.Flags().String("description", "", "Description")
+	iamOrgsUpdateCmd.Flags().String("type", "", "Organization type, e.g. Hospital")
+	iamOrgsUpdateCmd.Flags().String("external-id", "", "External ID")
+}
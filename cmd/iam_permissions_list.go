@@ -0,0 +1,89 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+// iamPermissionsListCmd represents the list command
+var iamPermissionsListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"l", "ls"},
+	Short:   "List permissions",
+	Long: `Lists the IAM permission catalog.
+
+Use --search to only show permissions whose name, category or description
+contains the given text, or --role to list the permissions of a role.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+		opts := &iam.GetPermissionOptions{}
+		if role, _ := cmd.Flags().GetString("role"); role != "" {
+			if currentWorkspace.IAMSelectedOrg == "" {
+				return errNoOrgSelected
+			}
+			r, err := resolveRole(iamClient, currentWorkspace.IAMSelectedOrg, role)
+			if err != nil {
+				return err
+			}
+			opts.RoleID = &r.ID
+		}
+		permissions, _, err := iamClient.Permissions.GetPermissions(opts)
+		if err != nil {
+			return fmt.Errorf("error retrieving permissions: %w", err)
+		}
+		if permissions == nil {
+			return fmt.Errorf("no permissions returned")
+		}
+		search, _ := cmd.Flags().GetString("search")
+		search = strings.ToLower(search)
+		list := make([]iam.Permission, 0)
+		for _, p := range *permissions {
+			if search != "" && !strings.Contains(strings.ToLower(p.Name+" "+p.Category+" "+p.Description), search) {
+				continue
+			}
+			list = append(list, p)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].Name < list[j].Name
+		})
+		rows := make([][]interface{}, 0, len(list))
+		for _, p := range list {
+			rows = append(rows, []interface{}{p.Name, p.Category, p.Type, p.Description})
+		}
+		return printOutput(list, []string{"permission", "category", "type", "description"}, rows)
+	},
+}
+
+func init() {
+	iamPermissionsCmd.AddCommand(iamPermissionsListCmd)
+	iamPermissionsListCmd.Flags().StringP("search", "s", "", "Only list permissions matching this text")
+	iamPermissionsListCmd.Flags().String("role", "", "List the permissions of this role (name or ID)")
+}
@@ -0,0 +1,461 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"gopkg.in/yaml.v3"
+)
+
+// iamConfig is the desired state of an organization as read by 'hs iam apply'
+type iamConfig struct {
+	Organization string        `json:"organization,omitempty" yaml:"organization,omitempty"`
+	Orgs         []orgConfig   `json:"orgs,omitempty" yaml:"orgs,omitempty"`
+	Roles        []roleConfig  `json:"roles,omitempty" yaml:"roles,omitempty"`
+	Groups       []groupConfig `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+type orgConfig struct {
+	Name        string `json:"name" yaml:"name"`
+	DisplayName string `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Type        string `json:"type,omitempty" yaml:"type,omitempty"`
+	ExternalID  string `json:"externalId,omitempty" yaml:"externalId,omitempty"`
+}
+
+type roleConfig struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Permissions []string `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+}
+
+type groupConfig struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Roles       []string `json:"roles,omitempty" yaml:"roles,omitempty"`
+	Users       []string `json:"users,omitempty" yaml:"users,omitempty"`
+	Services    []string `json:"services,omitempty" yaml:"services,omitempty"`
+	Devices     []string `json:"devices,omitempty" yaml:"devices,omitempty"`
+}
+
+// members returns the member references of the group by IAM member type
+func (g groupConfig) members() map[string][]string {
+	return map[string][]string{
+		iam.GroupMemberTypeUser:    g.Users,
+		iam.GroupMemberTypeService: g.Services,
+		iam.GroupMemberTypeDevice:  g.Devices,
+	}
+}
+
+// readIAMConfig reads and validates a desired state file, "-" reads stdin
+func readIAMConfig(file string) (*iamConfig, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var config iamConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, invalidInput("invalid IAM configuration %s: %v", file, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *iamConfig) validate() error {
+	seen := make(map[string]bool)
+	unique := func(kind, name string) error {
+		if name == "" {
+			return invalidInput("%s without a name", kind)
+		}
+		if seen[kind+"/"+name] {
+			return invalidInput("duplicate %s '%s'", kind, name)
+		}
+		seen[kind+"/"+name] = true
+		return nil
+	}
+	for _, o := range c.Orgs {
+		if err := unique("org", o.Name); err != nil {
+			return err
+		}
+	}
+	for i, r := range c.Roles {
+		if err := unique("role", r.Name); err != nil {
+			return err
+		}
+		for j, p := range r.Permissions {
+			c.Roles[i].Permissions[j] = strings.ToUpper(strings.TrimSpace(p))
+		}
+	}
+	for _, g := range c.Groups {
+		if err := unique("group", g.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// liveRole and liveGroup are the parts of existing roles and groups a plan compares
+type liveRole struct {
+	ID          string
+	Permissions []string
+}
+
+type liveGroup struct {
+	ID          string
+	Description string
+	Roles       []string
+	// Members maps member type to member ID to a display name
+	Members map[string]map[string]string
+}
+
+// iamState is a snapshot of the live IAM resources in an organization
+type iamState struct {
+	OrgID  string
+	Orgs   map[string]iam.Organization
+	Roles  map[string]*liveRole
+	Groups map[string]*liveGroup
+	// MemberIDs resolves the member references of the config per member type
+	MemberIDs map[string]map[string]string
+}
+
+// loadIAMState retrieves the live state of the resources config refers to.
+// With prune the undeclared groups and roles of the organization are loaded as well.
+func loadIAMState(client *iam.Client, orgID string, config *iamConfig, prune bool) (*iamState, error) {
+	state := &iamState{
+		OrgID:     orgID,
+		Orgs:      make(map[string]iam.Organization),
+		Roles:     make(map[string]*liveRole),
+		Groups:    make(map[string]*liveGroup),
+		MemberIDs: make(map[string]map[string]string),
+	}
+	if len(config.Orgs) > 0 {
+		children, err := listChildOrgs(client, orgID)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			state.Orgs[child.Name] = child
+		}
+	}
+
+	declaredRoles := make(map[string]bool)
+	for _, r := range config.Roles {
+		declaredRoles[r.Name] = true
+	}
+	roles, _, err := client.Roles.GetRoles(&iam.GetRolesOptions{OrganizationID: &orgID})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving roles: %w", err)
+	}
+	if roles != nil {
+		for i := range *roles {
+			role := (*roles)[i]
+			live := &liveRole{ID: role.ID}
+			if declaredRoles[role.Name] || prune {
+				if live.Permissions, err = rolePermissions(client, &role); err != nil {
+					return nil, err
+				}
+			}
+			state.Roles[role.Name] = live
+		}
+	}
+
+	declaredGroups := make(map[string]bool)
+	for _, g := range config.Groups {
+		declaredGroups[g.Name] = true
+	}
+	groups, _, err := client.Groups.GetGroups(&iam.GetGroupOptions{OrganizationID: &orgID})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving groups: %w", err)
+	}
+	if groups != nil {
+		for _, group := range *groups {
+			live := &liveGroup{ID: group.ID, Description: group.GroupDescription, Roles: []string{}, Members: make(map[string]map[string]string)}
+			state.Groups[group.GroupName] = live
+			if !declaredGroups[group.GroupName] && !prune {
+				continue
+			}
+			groupRoles, _, err := client.Groups.GetRoles(iam.Group{ID: group.ID})
+			if err != nil {
+				return nil, fmt.Errorf("error retrieving roles of group '%s': %w", group.GroupName, err)
+			}
+			if groupRoles != nil {
+				for _, role := range *groupRoles {
+					live.Roles = append(live.Roles, role.Name)
+				}
+			}
+			for _, memberType := range []string{iam.GroupMemberTypeUser, iam.GroupMemberTypeService, iam.GroupMemberTypeDevice} {
+				memberType := memberType
+				scimGroup, _, err := client.Groups.SCIMGetGroupByIDAll(group.ID, &iam.SCIMGetGroupOptions{
+					IncludeGroupMembersType: &memberType,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("error retrieving members of group '%s': %w", group.GroupName, err)
+				}
+				members := make(map[string]string)
+				for _, resource := range scimGroup.ExtensionGroup.GroupMembers.Resources {
+					name := resource.ID
+					switch {
+					case resource.UserName != "":
+						name = resource.UserName
+					case resource.ServiceId != "":
+						name = resource.ServiceId
+					case resource.LoginID != "":
+						name = resource.LoginID
+					}
+					members[resource.ID] = name
+				}
+				live.Members[memberType] = members
+			}
+		}
+	}
+
+	for _, g := range config.Groups {
+		for memberType, refs := range g.members() {
+			if state.MemberIDs[memberType] == nil {
+				state.MemberIDs[memberType] = make(map[string]string)
+			}
+			for _, ref := range refs {
+				if _, ok := state.MemberIDs[memberType][ref]; ok {
+					continue
+				}
+				id, err := resolveMember(client, memberType, ref)
+				if err != nil {
+					return nil, fmt.Errorf("group '%s': %w", g.Name, err)
+				}
+				state.MemberIDs[memberType][ref] = id
+			}
+		}
+	}
+	return state, nil
+}
+
+// planAction is a single change of an IAM plan
+type planAction struct {
+	Op         string   `json:"op"`
+	Kind       string   `json:"kind"`
+	Name       string   `json:"name"`
+	Value      string   `json:"value,omitempty"`
+	MemberType string   `json:"memberType,omitempty"`
+	MemberID   string   `json:"memberId,omitempty"`
+	Changes    []string `json:"changes,omitempty"`
+}
+
+const (
+	opCreate = "create"
+	opUpdate = "update"
+	opDelete = "delete"
+	opAdd    = "add"
+	opRemove = "remove"
+
+	kindOrg        = "org"
+	kindRole       = "role"
+	kindGroup      = "group"
+	kindPermission = "permission"
+	kindAssignment = "assignment"
+	kindMember     = "member"
+)
+
+func (a planAction) String() string {
+	sign := map[string]string{opCreate: "+", opAdd: "+", opUpdate: "~", opDelete: "-", opRemove: "-"}[a.Op]
+	switch a.Kind {
+	case kindPermission:
+		if a.Op == opAdd {
+			return fmt.Sprintf("%s add permission %s to role %s", sign, a.Value, a.Name)
+		}
+		return fmt.Sprintf("%s remove permission %s from role %s", sign, a.Value, a.Name)
+	case kindAssignment:
+		if a.Op == opAdd {
+			return fmt.Sprintf("%s assign role %s to group %s", sign, a.Value, a.Name)
+		}
+		return fmt.Sprintf("%s remove role %s from group %s", sign, a.Value, a.Name)
+	case kindMember:
+		memberType := strings.ToLower(a.MemberType)
+		if a.Op == opAdd {
+			return fmt.Sprintf("%s add %s %s to group %s", sign, memberType, a.Value, a.Name)
+		}
+		return fmt.Sprintf("%s remove %s %s from group %s", sign, memberType, a.Value, a.Name)
+	}
+	if len(a.Changes) > 0 {
+		return fmt.Sprintf("%s %s %s %s: %s", sign, a.Op, a.Kind, a.Name, strings.Join(a.Changes, ", "))
+	}
+	return fmt.Sprintf("%s %s %s %s", sign, a.Op, a.Kind, a.Name)
+}
+
+// planIAM computes the actions which bring the live state to the desired
+// config. Without prune nothing is removed. Actions are ordered so that
+// resources exist before they are referenced and are emptied before they are
+// deleted.
+func planIAM(config *iamConfig, state *iamState, prune bool) ([]planAction, error) {
+	var adds, removes []planAction
+
+	for _, o := range config.Orgs {
+		live, ok := state.Orgs[o.Name]
+		if !ok {
+			adds = append(adds, planAction{Op: opCreate, Kind: kindOrg, Name: o.Name})
+			continue
+		}
+		changes := make([]string, 0)
+		for _, f := range []struct{ name, live, desired string }{
+			{"displayName", live.DisplayName, o.DisplayName},
+			{"description", live.Description, o.Description},
+			{"type", live.Type, o.Type},
+			{"externalId", live.ExternalID, o.ExternalID},
+		} {
+			if f.desired != "" && f.desired != f.live {
+				changes = append(changes, f.name)
+			}
+		}
+		if len(changes) > 0 {
+			adds = append(adds, planAction{Op: opUpdate, Kind: kindOrg, Name: o.Name, Changes: changes})
+		}
+	}
+
+	declaredRoles := make(map[string]bool)
+	for _, r := range config.Roles {
+		declaredRoles[r.Name] = true
+		live, ok := state.Roles[r.Name]
+		if !ok {
+			adds = append(adds, planAction{Op: opCreate, Kind: kindRole, Name: r.Name})
+			live = &liveRole{}
+		}
+		diff := diffPermissions(live.Permissions, r.Permissions, true)
+		for _, p := range diff.Add {
+			adds = append(adds, planAction{Op: opAdd, Kind: kindPermission, Name: r.Name, Value: p})
+		}
+		if prune {
+			for _, p := range missingFrom(live.Permissions, r.Permissions) {
+				adds = append(adds, planAction{Op: opRemove, Kind: kindPermission, Name: r.Name, Value: p})
+			}
+		}
+	}
+
+	declaredGroups := make(map[string]bool)
+	for _, g := range config.Groups {
+		declaredGroups[g.Name] = true
+		live, ok := state.Groups[g.Name]
+		if !ok {
+			adds = append(adds, planAction{Op: opCreate, Kind: kindGroup, Name: g.Name})
+			live = &liveGroup{Members: map[string]map[string]string{}}
+		} else if g.Description != "" && g.Description != live.Description {
+			adds = append(adds, planAction{Op: opUpdate, Kind: kindGroup, Name: g.Name, Changes: []string{"description"}})
+		}
+		for _, role := range g.Roles {
+			_, exists := state.Roles[role]
+			if !declaredRoles[role] && (!exists || prune) {
+				return nil, invalidInput("group '%s' references role '%s' which is not declared in roles", g.Name, role)
+			}
+		}
+		for _, role := range missingFrom(g.Roles, live.Roles) {
+			adds = append(adds, planAction{Op: opAdd, Kind: kindAssignment, Name: g.Name, Value: role})
+		}
+		if prune {
+			for _, role := range missingFrom(live.Roles, g.Roles) {
+				removes = append(removes, planAction{Op: opRemove, Kind: kindAssignment, Name: g.Name, Value: role})
+			}
+		}
+		for _, memberType := range []string{iam.GroupMemberTypeUser, iam.GroupMemberTypeService, iam.GroupMemberTypeDevice} {
+			desired := make(map[string]bool)
+			for _, ref := range g.members()[memberType] {
+				id := state.MemberIDs[memberType][ref]
+				desired[id] = true
+				if _, ok := live.Members[memberType][id]; !ok {
+					adds = append(adds, planAction{Op: opAdd, Kind: kindMember, Name: g.Name, Value: ref, MemberType: memberType, MemberID: id})
+				}
+			}
+			if prune {
+				for _, id := range sortedMapKeys(live.Members[memberType]) {
+					if !desired[id] {
+						removes = append(removes, planAction{Op: opRemove, Kind: kindMember, Name: g.Name,
+							Value: live.Members[memberType][id], MemberType: memberType, MemberID: id})
+					}
+				}
+			}
+		}
+	}
+
+	if prune {
+		for _, name := range sortedMapKeys(state.Groups) {
+			if declaredGroups[name] {
+				continue
+			}
+			live := state.Groups[name]
+			for _, memberType := range sortedMapKeys(live.Members) {
+				for _, id := range sortedMapKeys(live.Members[memberType]) {
+					removes = append(removes, planAction{Op: opRemove, Kind: kindMember, Name: name,
+						Value: live.Members[memberType][id], MemberType: memberType, MemberID: id})
+				}
+			}
+			for _, role := range live.Roles {
+				removes = append(removes, planAction{Op: opRemove, Kind: kindAssignment, Name: name, Value: role})
+			}
+			removes = append(removes, planAction{Op: opDelete, Kind: kindGroup, Name: name})
+		}
+		for _, name := range sortedMapKeys(state.Roles) {
+			if !declaredRoles[name] {
+				removes = append(removes, planAction{Op: opDelete, Kind: kindRole, Name: name})
+			}
+		}
+	}
+	// Members and roles leave groups before groups and roles are deleted
+	sort.SliceStable(removes, func(i, j int) bool {
+		return removes[i].Op == opRemove && removes[j].Op == opDelete
+	})
+	return append(adds, removes...), nil
+}
+
+// missingFrom returns the entries of list which are not in other, in order
+func missingFrom(list, other []string) []string {
+	has := make(map[string]bool, len(other))
+	for _, o := range other {
+		has[o] = true
+	}
+	missing := make([]string, 0)
+	for _, l := range list {
+		if !has[l] {
+			missing = append(missing, l)
+		}
+	}
+	return missing
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
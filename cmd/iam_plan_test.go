@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dip-software/go-dip-api/iam"
+)
+
+func testIAMState() *iamState {
+	return &iamState{
+		OrgID: "org",
+		Orgs:  map[string]iam.Organization{"team-a": {ID: "team-a-id", Name: "team-a", Description: "old"}},
+		Roles: map[string]*liveRole{
+			"READER": {ID: "reader-id", Permissions: []string{"PATIENT.READ", "PATIENT.WRITE"}},
+			"LEGACY": {ID: "legacy-id", Permissions: []string{"DEVICE.READ"}},
+		},
+		Groups: map[string]*liveGroup{
+			"readers": {ID: "readers-id", Roles: []string{"READER"}, Members: map[string]map[string]string{
+				iam.GroupMemberTypeUser: {"bob-id": "bob"},
+			}},
+			"old": {ID: "old-id", Roles: []string{"LEGACY"}, Members: map[string]map[string]string{
+				iam.GroupMemberTypeUser: {"carol-id": "carol"},
+			}},
+		},
+		MemberIDs: map[string]map[string]string{
+			iam.GroupMemberTypeUser: {"alice": "alice-id", "bob": "bob-id"},
+		},
+	}
+}
+
+func testIAMConfig(t *testing.T) *iamConfig {
+	file := filepath.Join(t.TempDir(), "iam.yaml")
+	content := `orgs:
+  - name: team-a
+    description: new
+  - name: team-b
+roles:
+  - name: READER
+    permissions: [patient.read, observation.read]
+groups:
+  - name: readers
+    roles: [READER]
+    users: [alice, bob]
+`
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := readIAMConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+func planStrings(actions []planAction) []string {
+	lines := make([]string, 0, len(actions))
+	for _, action := range actions {
+		lines = append(lines, action.String())
+	}
+	return lines
+}
+
+func TestPlanIAM(t *testing.T) {
+	actions, err := planIAM(testIAMConfig(t), testIAMState(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"~ update org team-a: description",
+		"+ create org team-b",
+		"+ add permission OBSERVATION.READ to role READER",
+		"+ add user alice to group readers",
+	}
+	lines := planStrings(actions)
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d actions, got %v", len(expected), lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("action %d: expected %q, got %q", i, expected[i], lines[i])
+		}
+	}
+}
+
+func TestPlanIAMPrune(t *testing.T) {
+	actions, err := planIAM(testIAMConfig(t), testIAMState(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"~ update org team-a: description",
+		"+ create org team-b",
+		"+ add permission OBSERVATION.READ to role READER",
+		"- remove permission PATIENT.WRITE from role READER",
+		"+ add user alice to group readers",
+		"- remove user carol from group old",
+		"- remove role LEGACY from group old",
+		"- delete group old",
+		"- delete role LEGACY",
+	}
+	lines := planStrings(actions)
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d actions, got %v", len(expected), lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("action %d: expected %q, got %q", i, expected[i], lines[i])
+		}
+	}
+}
+
+func TestPlanIAMUndeclaredRole(t *testing.T) {
+	config := testIAMConfig(t)
+	config.Groups[0].Roles = append(config.Groups[0].Roles, "LEGACY")
+	if _, err := planIAM(config, testIAMState(), false); err != nil {
+		t.Errorf("existing role should be allowed without prune: %v", err)
+	}
+	if _, err := planIAM(config, testIAMState(), true); err == nil {
+		t.Error("expected error for undeclared role with prune")
+	}
+}
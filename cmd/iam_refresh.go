@@ -24,32 +24,172 @@ THE SOFTWARE.
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/avast/retry-go/v4"
-	"github.com/dip-software/go-dip-api/iam"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/dip-software/go-dip-api/iam"
 	"github.com/spf13/cobra"
 )
 
 // TokenResponse is a struct that matches the JSON response structure
 type TokenResponse struct {
 	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+const (
+	// refreshMinInterval keeps short lived tokens from causing a tight refresh loop
+	refreshMinInterval    = 30 * time.Second
+	refreshInitialBackoff = 5 * time.Second
+)
+
+type refreshOptions struct {
+	keyFile             string
+	tokenFile           string
+	tokenExchangeIssuer string
+	connectorID         string
+	clientID            string
+	clientSecret        string
+}
+
+// refreshStatus tracks the state of the refresh loop for readiness and health probes
+type refreshStatus struct {
+	sync.Mutex
+	expires   time.Time
+	lastError error
+}
+
+func (s *refreshStatus) succeeded(expires time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	s.expires = expires
+	s.lastError = nil
+}
+
+func (s *refreshStatus) failed(err error) {
+	s.Lock()
+	defer s.Unlock()
+	s.lastError = err
+}
+
+// ready reports whether the last written token is still valid
+func (s *refreshStatus) ready(now time.Time) bool {
+	s.Lock()
+	defer s.Unlock()
+	return !s.expires.IsZero() && now.Before(s.expires)
+}
+
+// validFor returns how long the last written token remains valid, 0 when it is not
+func (s *refreshStatus) validFor(now time.Time) time.Duration {
+	if !s.ready(now) {
+		return 0
+	}
+	s.Lock()
+	defer s.Unlock()
+	return s.expires.Sub(now)
+}
+
+// ServeHTTP serves /healthz, which succeeds while the refresh loop runs, and
+// /readyz, which succeeds while the token file holds a valid token
+func (s *refreshStatus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/healthz":
+		_, _ = fmt.Fprintln(w, "ok")
+	case "/readyz":
+		if s.ready(time.Now()) {
+			_, _ = fmt.Fprintln(w, "ok")
+			return
+		}
+		s.Lock()
+		lastError := s.lastError
+		s.Unlock()
+		message := "no valid token"
+		if lastError != nil {
+			message += ": " + lastError.Error()
+		}
+		http.Error(w, message, http.StatusServiceUnavailable)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// nextRefresh returns the time to wait before refreshing a token expiring at
+// expires, a fraction of its remaining lifetime capped at max when set
+func nextRefresh(now, expires time.Time, fraction float64, max time.Duration) time.Duration {
+	wait := time.Duration(float64(expires.Sub(now)) * fraction)
+	if max > 0 && wait > max {
+		wait = max
+	}
+	if wait < refreshMinInterval {
+		wait = refreshMinInterval
+	}
+	return wait
+}
+
+// refreshBackoff returns the exponential backoff for a failed attempt, capped at max
+func refreshBackoff(attempt int, max time.Duration) time.Duration {
+	wait := refreshInitialBackoff
+	for i := 1; i < attempt && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	return wait
+}
+
+// sleepRefresh waits for wait or until ctx is done. When the token expires
+// during the wait the ready file is removed at that moment, so it never
+// outlives a valid token.
+func sleepRefresh(ctx context.Context, wait time.Duration, status *refreshStatus, readyFile string) {
+	deadline := time.Now().Add(wait)
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return
+		}
+		if valid := status.validFor(time.Now()); readyFile != "" && valid > 0 && valid < remaining {
+			remaining = valid
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(remaining):
+		}
+		if readyFile != "" && !status.ready(time.Now()) {
+			_ = os.Remove(readyFile)
+		}
+	}
 }
 
-// iamTokenCmd represents the token command
+// iamRefreshCmd represents the refresh command
 var iamRefreshCmd = &cobra.Command{
 	Use:   "refresh",
 	Short: "Continuously refreshes a service identity token",
-	Long:  `Refreshes access token, useful for sidecar processes.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Refreshes access token, useful for sidecar processes.
+
+The token is refreshed after a fraction of its lifetime, see --refresh-fraction,
+or at least every --every seconds when set. Failures are retried with
+exponential backoff up to --max-backoff until the process is stopped using
+SIGINT or SIGTERM. The token file is replaced atomically and only readable by
+the owner.
+
+For Kubernetes probes --ready-file is written while a valid token is available,
+and --health-addr serves /healthz and /readyz.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Configure log output
 		logLevel := &slog.LevelVar{}
 		logLevel.Set(slog.LevelInfo)
@@ -61,144 +201,197 @@ var iamRefreshCmd = &cobra.Command{
 		})))
 
 		every, _ := cmd.Flags().GetInt64("every")
-		clientId, _ := cmd.Flags().GetString("client-id")
-		clientSecret, _ := cmd.Flags().GetString("client-secret")
+		fraction, _ := cmd.Flags().GetFloat64("refresh-fraction")
+		maxBackoff, _ := cmd.Flags().GetDuration("max-backoff")
+		readyFile, _ := cmd.Flags().GetString("ready-file")
+		healthAddr, _ := cmd.Flags().GetString("health-addr")
+		var opts refreshOptions
+		opts.keyFile, _ = cmd.Flags().GetString("key-file")
+		opts.tokenFile, _ = cmd.Flags().GetString("token-file")
+		opts.tokenExchangeIssuer, _ = cmd.Flags().GetString("token-exchange-issuer")
+		opts.connectorID, _ = cmd.Flags().GetString("connector-id")
+		opts.clientID, _ = cmd.Flags().GetString("client-id")
+		opts.clientSecret, _ = cmd.Flags().GetString("client-secret")
 
-		if every == 0 {
-			slog.Error("every must be > 0", "every", every)
-			return
+		if every < 0 {
+			return invalidInput("every must be >= 0")
+		}
+		if fraction <= 0 || fraction >= 1 {
+			return invalidInput("refresh-fraction must be between 0 and 1")
+		}
+		if maxBackoff < refreshInitialBackoff {
+			return invalidInput("max-backoff must be at least %s", refreshInitialBackoff)
+		}
+		if opts.keyFile == "" {
+			return invalidInput("key-file is required")
 		}
-		tokenExchangeIssuer, _ := cmd.Flags().GetString("token-exchange-issuer")
-		tokenFile, _ := cmd.Flags().GetString("token-file")
-		// Loop here
-		retries := 3
 
-		for {
-			connectorId, _ := cmd.Flags().GetString("connector-id")
-			keyFile, _ := cmd.Flags().GetString("key-file")
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
 
-			if keyFile == "" {
-				slog.Error("key-file is required")
-				return
+		status := &refreshStatus{}
+		if healthAddr != "" {
+			listener, err := net.Listen("tcp", healthAddr)
+			if err != nil {
+				return fmt.Errorf("unable to listen on %s: %w", healthAddr, err)
 			}
-
-			err := retry.Do(func() error {
-				var key Key
-				base64Key, err := os.ReadFile(keyFile)
-				if err != nil {
-					slog.Error("error reading private key", "error", err)
-					return err
+			server := &http.Server{Handler: status, ReadHeaderTimeout: 5 * time.Second}
+			go func() {
+				if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					slog.Error("health endpoint failed", "error", err)
 				}
-				// Decode the base64 token
-				decoded, err := base64.StdEncoding.DecodeString(string(base64Key))
-				if err != nil {
-					if unmarshalErr := json.Unmarshal(base64Key, &key); err != nil {
-						slog.Error("key error", "unmarshal", unmarshalErr, "error", err)
-						return err
+			}()
+			defer func() {
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = server.Shutdown(shutdownCtx)
+			}()
+			slog.Info("serving health endpoints", "addr", listener.Addr().String())
+		}
+		if readyFile != "" {
+			defer func() {
+				_ = os.Remove(readyFile)
+			}()
+		}
+
+		attempt := 0
+		for ctx.Err() == nil {
+			var wait time.Duration
+			expires, err := refreshToken(ctx, opts)
+			if err == nil {
+				attempt = 0
+				status.succeeded(expires)
+				if readyFile != "" {
+					if err := writeFileAtomic(readyFile, []byte(time.Now().UTC().Format(time.RFC3339)+"\n"), 0600); err != nil {
+						slog.Error("error writing ready file", "error", err)
 					}
-					// an already base64 decoded key was passed
-					decoded = base64Key
 				}
-				// Unmarshal the JSON data
-				if err := json.Unmarshal(decoded, &key); err != nil {
-					slog.Error("error unmarshalling key", "error", err)
-					return err
-				}
-
-				iamClient, err := iam.NewClient(http.DefaultClient, &iam.Config{
-					Region:         key.Region,
-					Environment:    key.Environment,
-					OAuth2ClientID: clientId,
-					OAuth2Secret:   clientSecret,
-				})
-				if err != nil {
-					slog.Error("error initializing IAM client", "error", err)
-					return err
+				wait = nextRefresh(time.Now(), expires, fraction, time.Duration(every)*time.Second)
+				slog.Info("sleeping", "seconds", int64(wait.Seconds()), "expires", expires.UTC().Format(time.RFC3339))
+			} else {
+				if ctx.Err() != nil {
+					continue
 				}
-				slog.Info("logging in", "serviceID", key.ID)
-				err = iamClient.ServiceLogin(iam.Service{
-					ServiceID:  key.ID,
-					PrivateKey: string(key.PrivateKey),
-				})
-				if err != nil {
-					slog.Error("error logging in", "error", err)
-					return err
+				attempt++
+				status.failed(err)
+				if readyFile != "" && !status.ready(time.Now()) {
+					_ = os.Remove(readyFile)
 				}
+				wait = refreshBackoff(attempt, maxBackoff)
+				slog.Error("failed to get token", "error", err, "attempt", attempt, "retryInSeconds", int64(wait.Seconds()))
+			}
+			sleepRefresh(ctx, wait, status, readyFile)
+		}
+		slog.Info("shutting down")
+		return nil
+	},
+}
 
-				token, _ := iamClient.Token()
-				if tokenExchangeIssuer != "" {
-					// Prepare the data to be sent in the request body
-					slog.Info("exchanging token", "issuer", tokenExchangeIssuer)
-					data := url.Values{}
-					data.Set("connector_id", connectorId)
-					data.Set("grant_type", "urn:ietf:params:oauth:grant-type:token-exchange")
-					data.Set("scope", "openid groups federated:id")
-					data.Set("requested_token_type", "urn:ietf:params:oauth:token-type:access_token")
-					data.Set("subject_token", token)
-					data.Set("subject_token_type", "urn:ietf:params:oauth:token-type:access_token")
-					// Create a new request
-					req, err := http.NewRequest("POST", tokenExchangeIssuer+"/token", bytes.NewBufferString(data.Encode()))
-					if err != nil {
-						return err
-					}
-
-					// Set the Authorization header
-					auth := base64.StdEncoding.EncodeToString([]byte(clientId + ":" + clientSecret))
-					req.Header.Add("Authorization", "Basic "+auth)
+// refreshToken logs in using the service key, optionally exchanges the token
+// and writes it to the token file. It returns when the written token expires.
+func refreshToken(ctx context.Context, opts refreshOptions) (time.Time, error) {
+	base64Key, err := os.ReadFile(opts.keyFile)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error reading private key: %w", err)
+	}
+	key, err := decodeKey(base64Key)
+	if err != nil {
+		return time.Time{}, err
+	}
 
-					// Set the Content-Type header
-					req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	iamClient, err := iam.NewClient(http.DefaultClient, &iam.Config{
+		Region:         key.Region,
+		Environment:    key.Environment,
+		OAuth2ClientID: opts.clientID,
+		OAuth2Secret:   opts.clientSecret,
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error initializing IAM client: %w", err)
+	}
+	slog.Info("logging in", "serviceID", key.ID)
+	err = iamClient.ServiceLogin(iam.Service{
+		ServiceID:  key.ID,
+		PrivateKey: key.PrivateKey,
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error logging in: %w", err)
+	}
+	token, _ := iamClient.Token()
+	expires := time.Unix(iamClient.Expires(), 0)
 
-					// Create an HTTP client and send the request
-					client := &http.Client{}
-					resp, err := client.Do(req)
-					if err != nil {
-						return err
-					}
-					defer resp.Body.Close()
-					// Read and print the response body
-					body, err := io.ReadAll(resp.Body)
-					if err != nil {
-						return err
-					}
-					// Parse the JSON response
-					var tokenResponse TokenResponse
-					if err := json.Unmarshal(body, &tokenResponse); err != nil {
-						return err
-					}
-					token = tokenResponse.AccessToken
-				}
-				if tokenFile != "" {
-					err = os.WriteFile(tokenFile, []byte(token), 0644)
-					if err != nil {
-						retries = retries - 1
-						slog.Error("error writing token file", "error", err)
-					} else {
-						slog.Info("token written", "file", tokenFile)
-					}
-				}
-				if jsonOut {
-					data, _ := json.Marshal(tokenOutput{token})
-					fmt.Printf("%s\n", string(data))
-				}
-				return nil
-			}, retry.Attempts(uint(retries)), retry.Delay(5*time.Second))
-			if err != nil {
-				slog.Error("failed to get token", "error", err)
-				return
+	if opts.tokenExchangeIssuer != "" {
+		slog.Info("exchanging token", "issuer", opts.tokenExchangeIssuer)
+		exchanged, err := exchangeToken(ctx, opts, token)
+		if err != nil {
+			return time.Time{}, err
+		}
+		token = exchanged.AccessToken
+		if exchanged.ExpiresIn > 0 {
+			if exchangeExpires := time.Now().Add(time.Duration(exchanged.ExpiresIn) * time.Second); exchangeExpires.Before(expires) {
+				expires = exchangeExpires
 			}
-			// Wait for next cycle
-			slog.Info("sleeping", "seconds", every)
-			time.Sleep(time.Duration(every) * time.Second)
 		}
-	},
+	}
+	if opts.tokenFile != "" {
+		if err := writeFileAtomic(opts.tokenFile, []byte(token), 0600); err != nil {
+			return time.Time{}, fmt.Errorf("error writing token file: %w", err)
+		}
+		slog.Info("token written", "file", opts.tokenFile)
+	}
+	if !tableOutput() {
+		_ = printObject(tokenOutput{token})
+	}
+	return expires, nil
+}
+
+// exchangeToken exchanges an IAM access token with the token exchange issuer
+func exchangeToken(ctx context.Context, opts refreshOptions, token string) (*TokenResponse, error) {
+	data := url.Values{}
+	data.Set("connector_id", opts.connectorID)
+	data.Set("grant_type", "urn:ietf:params:oauth:grant-type:token-exchange")
+	data.Set("scope", "openid groups federated:id")
+	data.Set("requested_token_type", "urn:ietf:params:oauth:token-type:access_token")
+	data.Set("subject_token", token)
+	data.Set("subject_token_type", "urn:ietf:params:oauth:token-type:access_token")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opts.tokenExchangeIssuer+"/token", bytes.NewBufferString(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte(opts.clientID + ":" + opts.clientSecret))
+	req.Header.Add("Authorization", "Basic "+auth)
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error exchanging token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token exchange failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	var tokenResponse TokenResponse
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return nil, fmt.Errorf("error decoding token exchange response: %w", err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return nil, fmt.Errorf("token exchange returned no access token")
+	}
+	return &tokenResponse, nil
 }
 
 func init() {
 	iamCmd.AddCommand(iamRefreshCmd)
 
 	iamRefreshCmd.Flags().String("key-file", "", "A file containing the key")
-	iamRefreshCmd.Flags().Int64("every", 900, "Refresh every n seconds")
+	iamRefreshCmd.Flags().Int64("every", 0, "Refresh at least every n seconds, 0 refreshes based on the token expiry only")
+	iamRefreshCmd.Flags().Float64("refresh-fraction", 0.5, "Refresh after this fraction of the token lifetime")
+	iamRefreshCmd.Flags().Duration("max-backoff", 5*time.Minute, "Maximum delay between retries after a failure")
+	iamRefreshCmd.Flags().String("ready-file", "", "A file which exists while a valid token is available")
+	iamRefreshCmd.Flags().String("health-addr", "", "Address to serve /healthz and /readyz on, e.g. :8080")
 	iamRefreshCmd.Flags().String("token-file", "token.txt", "The file to write the token to")
 	iamRefreshCmd.Flags().String("token-exchange-issuer", "", "Exchanges the token with the specified issuer")
 	iamRefreshCmd.Flags().String("connector-id", "hsdp", "The connector ID to use")
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNextRefresh(t *testing.T) {
+	now := time.Now()
+	for _, tc := range []struct {
+		expires  time.Duration
+		fraction float64
+		max      time.Duration
+		expected time.Duration
+	}{
+		{30 * time.Minute, 0.5, 0, 15 * time.Minute},
+		{30 * time.Minute, 0.75, 10 * time.Minute, 10 * time.Minute},
+		{10 * time.Second, 0.5, 0, refreshMinInterval},
+		{-time.Minute, 0.5, 0, refreshMinInterval},
+	} {
+		if wait := nextRefresh(now, now.Add(tc.expires), tc.fraction, tc.max); wait != tc.expected {
+			t.Errorf("expires in %s at %.2f max %s: expected %s, got %s", tc.expires, tc.fraction, tc.max, tc.expected, wait)
+		}
+	}
+}
+
+func TestRefreshBackoff(t *testing.T) {
+	max := time.Minute
+	expected := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, want := range expected {
+		if got := refreshBackoff(i+1, max); got != want {
+			t.Errorf("attempt %d: expected %s, got %s", i+1, want, got)
+		}
+	}
+	if got := refreshBackoff(1000, max); got != max {
+		t.Errorf("expected backoff to stay at %s, got %s", max, got)
+	}
+}
+
+func TestRefreshStatusProbes(t *testing.T) {
+	status := &refreshStatus{}
+	probe := func(path string) int {
+		w := httptest.NewRecorder()
+		status.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+	if code := probe("/healthz"); code != http.StatusOK {
+		t.Errorf("healthz: expected 200, got %d", code)
+	}
+	if code := probe("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("readyz before first token: expected 503, got %d", code)
+	}
+	status.succeeded(time.Now().Add(time.Hour))
+	status.failed(errors.New("login failed"))
+	if code := probe("/readyz"); code != http.StatusOK {
+		t.Errorf("readyz with valid token: expected 200, got %d", code)
+	}
+	status.succeeded(time.Now().Add(-time.Second))
+	if code := probe("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("readyz with expired token: expected 503, got %d", code)
+	}
+	if code := probe("/other"); code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", code)
+	}
+}
+
+func TestSleepRefreshRemovesReadyFileAtExpiry(t *testing.T) {
+	readyFile := filepath.Join(t.TempDir(), "ready")
+	if err := os.WriteFile(readyFile, []byte("ready\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	status := &refreshStatus{}
+	status.succeeded(time.Now().Add(50 * time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		sleepRefresh(ctx, time.Hour, status, readyFile)
+		close(done)
+	}()
+	for {
+		if _, err := os.Stat(readyFile); os.IsNotExist(err) {
+			break
+		}
+		if ctx.Err() != nil {
+			t.Fatal("expected the ready file to be removed when the token expired")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}
@@ -22,6 +22,12 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dip-software/go-dip-api/iam"
 	"github.com/spf13/cobra"
 )
 
@@ -31,8 +37,8 @@ var iamRolesCmd = &cobra.Command{
 	Aliases: []string{"r"},
 	Short:   "Manage roles",
 	Long:    `Manages IAM roles.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -40,3 +46,158 @@ func init() {
 	iamCmd.AddCommand(iamRolesCmd)
 
 }
+
+// resolveRole finds the role named nameOrID in org, falling back to a lookup by ID
+func resolveRole(client *iam.Client, org, nameOrID string) (*iam.Role, error) {
+	roles, _, err := client.Roles.GetRoles(&iam.GetRolesOptions{
+		Name:           &nameOrID,
+		OrganizationID: &org,
+	})
+	if err == nil && roles != nil && len(*roles) == 1 {
+		return &(*roles)[0], nil
+	}
+	if err == nil && roles != nil && len(*roles) > 1 {
+		return nil, invalidInput("role name '%s' is ambiguous, use the role ID instead", nameOrID)
+	}
+	role, _, err := client.Roles.GetRoleByID(nameOrID)
+	if err != nil {
+		if classifyError(err) == kindNotFound {
+			return nil, notFound("role '%s' not found", nameOrID)
+		}
+		return nil, fmt.Errorf("error retrieving role '%s': %w", nameOrID, err)
+	}
+	return role, nil
+}
+
+// roleArgs returns the role given as first argument, requiring a selected organization
+func roleArgs(cmd *cobra.Command, args []string) (*iam.Client, *iam.Role, error) {
+	if currentWorkspace.IAMSelectedOrg == "" {
+		return nil, nil, errNoOrgSelected
+	}
+	iamClient, err := getIAMClient(cmd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error initalizing IAM client: %w", err)
+	}
+	role, err := resolveRole(iamClient, currentWorkspace.IAMSelectedOrg, args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	return iamClient, role, nil
+}
+
+// rolePermissions returns the sorted permission names of role. Names are upper
+// cased so they compare equal to permissions given on the command line.
+func rolePermissions(client *iam.Client, role *iam.Role) ([]string, error) {
+	permissions, _, err := client.Roles.GetRolePermissions(*role)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving permissions of role '%s': %w", role.Name, err)
+	}
+	names := make([]string, 0)
+	if permissions != nil {
+		for _, name := range *permissions {
+			names = append(names, strings.ToUpper(name))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+type permissionDiff struct {
+	Role      string   `json:"role"`
+	ID        string   `json:"id"`
+	Add       []string `json:"add"`
+	Remove    []string `json:"remove"`
+	Unchanged []string `json:"unchanged"`
+	Applied   bool     `json:"applied"`
+}
+
+// diffPermissions determines which of the requested permissions change when
+// adding them to or removing them from current
+func diffPermissions(current, requested []string, add bool) permissionDiff {
+	has := make(map[string]bool, len(current))
+	for _, p := range current {
+		has[p] = true
+	}
+	diff := permissionDiff{Add: []string{}, Remove: []string{}, Unchanged: []string{}}
+	seen := make(map[string]bool, len(requested))
+	for _, p := range requested {
+		p = strings.ToUpper(strings.TrimSpace(p))
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		switch {
+		case add && !has[p]:
+			diff.Add = append(diff.Add, p)
+		case !add && has[p]:
+			diff.Remove = append(diff.Remove, p)
+		default:
+			diff.Unchanged = append(diff.Unchanged, p)
+		}
+	}
+	sort.Strings(diff.Add)
+	sort.Strings(diff.Remove)
+	sort.Strings(diff.Unchanged)
+	return diff
+}
+
+func (d permissionDiff) print() {
+	fmt.Printf("role %s (%s)\n", d.Role, d.ID)
+	for _, p := range d.Add {
+		fmt.Printf("+ %s\n", p)
+	}
+	for _, p := range d.Remove {
+		fmt.Printf("- %s\n", p)
+	}
+	for _, p := range d.Unchanged {
+		fmt.Printf("  %s (unchanged)\n", p)
+	}
+}
+
+// changeRolePermissions shows the permission changes for the role given as first
+// argument and applies them unless --dry-run is set
+func changeRolePermissions(cmd *cobra.Command, args []string, add bool) error {
+	client, role, err := roleArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	current, err := rolePermissions(client, role)
+	if err != nil {
+		return err
+	}
+	diff := diffPermissions(current, args[1:], add)
+	diff.Role = role.Name
+	diff.ID = role.ID
+	if tableOutput() {
+		diff.print()
+	}
+	changes := len(diff.Add) + len(diff.Remove)
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if dryRun || changes == 0 {
+		if !tableOutput() {
+			return printObject(diff)
+		}
+		if changes == 0 {
+			fmt.Fprintf(os.Stderr, "nothing to change\n")
+		} else {
+			fmt.Fprintf(os.Stderr, "dry run, %d permission(s) not changed\n", changes)
+		}
+		return nil
+	}
+	for _, p := range diff.Add {
+		if _, _, err := client.Roles.AddRolePermission(*role, p); err != nil {
+			return fmt.Errorf("error adding permission %s to role '%s': %w", p, role.Name, err)
+		}
+	}
+	for _, p := range diff.Remove {
+		if _, _, err := client.Roles.RemoveRolePermission(*role, p); err != nil {
+			return fmt.Errorf("error removing permission %s from role '%s': %w", p, role.Name, err)
+		}
+	}
+	diff.Applied = true
+	if tableOutput() {
+		fmt.Fprintf(os.Stderr, "%d permission(s) changed\n", changes)
+		return nil
+	}
+	return printObject(diff)
+}
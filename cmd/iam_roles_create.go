@@ -0,0 +1,70 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// iamRolesCreateCmd represents the create command
+var iamRolesCreateCmd = &cobra.Command{
+	Use:     "create <name>",
+	Aliases: []string{"c", "new"},
+	Short:   "Create a role",
+	Long: `Creates a role in the selected organization, optionally with an initial
+set of permissions.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if currentWorkspace.IAMSelectedOrg == "" {
+			return errNoOrgSelected
+		}
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+		description, _ := cmd.Flags().GetString("description")
+		permissions, _ := cmd.Flags().GetStringSlice("permission")
+		role, _, err := iamClient.Roles.CreateRole(args[0], description, currentWorkspace.IAMSelectedOrg)
+		if err != nil {
+			return fmt.Errorf("error creating role '%s': %w", args[0], err)
+		}
+		if role == nil {
+			return fmt.Errorf("role '%s' not returned after create", args[0])
+		}
+		diff := diffPermissions(nil, permissions, true)
+		for _, p := range diff.Add {
+			if _, _, err := iamClient.Roles.AddRolePermission(*role, p); err != nil {
+				return fmt.Errorf("role '%s' created but adding permission %s failed: %w", role.Name, p, err)
+			}
+		}
+		return printOutput(role, []string{"role", "id", "permissions"},
+			[][]interface{}{{role.Name, role.ID, len(diff.Add)}})
+	},
+}
+
+func init() {
+	iamRolesCmd.AddCommand(iamRolesCreateCmd)
+	iamRolesCreateCmd.Flags().String("description", "", "Description")
+	iamRolesCreateCmd.Flags().StringSliceP("permission", "p", nil, "Permission to add, can be repeated")
+}
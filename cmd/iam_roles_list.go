@@ -22,10 +22,8 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
-	"github.com/cheynewallace/tabby"
 	"github.com/dip-software/go-dip-api/iam"
 
 	"github.com/spf13/cobra"
@@ -37,11 +35,10 @@ var iamRolesListCmd = &cobra.Command{
 	Aliases: []string{"l"},
 	Short:   "List roles",
 	Long:    `Lists IAM roles.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		iamClient, err := getIAMClient(cmd)
 		if err != nil {
-			fmt.Printf("error initalizing IAM client: %v\n", err)
-			return
+			return fmt.Errorf("error initalizing IAM client: %w", err)
 		}
 		opts := &iam.GetRolesOptions{}
 		if name, err := cmd.Flags().GetString("name"); err == nil && name != "" {
@@ -52,23 +49,15 @@ var iamRolesListCmd = &cobra.Command{
 		}
 		roles, _, err := iamClient.Roles.GetRoles(opts)
 		if err != nil {
-			fmt.Printf("error retrieving roles: %v\n", err)
-			return
+			return fmt.Errorf("error retrieving roles: %w", err)
 		}
-		if jsonOut {
-			data, _ := json.Marshal(*roles)
-			fmt.Printf("%s\n", string(data))
-			return
-		}
-		t := tabby.New()
-		t.AddHeader("role", "id", "description")
+		rows := make([][]interface{}, 0)
 		for _, role := range *roles {
-			t.AddLine(role.Name,
+			rows = append(rows, []interface{}{role.Name,
 				role.ID,
-				role.Description)
+				role.Description})
 		}
-		t.Print()
-		_ = currentWorkspace.saveWithIAM(iamClient)
+		return printOutput(*roles, []string{"role", "id", "description"}, rows)
 	},
 }
 
@@ -0,0 +1,54 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// iamRolesPermissionsCmd represents the permissions command
+var iamRolesPermissionsCmd = &cobra.Command{
+	Use:     "permissions <role>",
+	Aliases: []string{"perms"},
+	Short:   "List role permissions",
+	Long:    `Lists the permissions of a role, identified by name or ID.`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iamClient, role, err := roleArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		permissions, err := rolePermissions(iamClient, role)
+		if err != nil {
+			return err
+		}
+		rows := make([][]interface{}, 0, len(permissions))
+		for _, p := range permissions {
+			rows = append(rows, []interface{}{p})
+		}
+		return printOutput(permissions, []string{"permission"}, rows)
+	},
+}
+
+func init() {
+	iamRolesCmd.AddCommand(iamRolesPermissionsCmd)
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffPermissions(t *testing.T) {
+	current := []string{"PATIENT.READ", "PATIENT.WRITE"}
+
+	diff := diffPermissions(current, []string{"patient.read", "DEVICE.READ", "DEVICE.READ"}, true)
+	if !reflect.DeepEqual(diff.Add, []string{"DEVICE.READ"}) || !reflect.DeepEqual(diff.Unchanged, []string{"PATIENT.READ"}) || len(diff.Remove) != 0 {
+		t.Errorf("unexpected add diff %+v", diff)
+	}
+
+	diff = diffPermissions(current, []string{"PATIENT.WRITE", "DEVICE.READ"}, false)
+	if !reflect.DeepEqual(diff.Remove, []string{"PATIENT.WRITE"}) || !reflect.DeepEqual(diff.Unchanged, []string{"DEVICE.READ"}) || len(diff.Add) != 0 {
+		t.Errorf("unexpected remove diff %+v", diff)
+	}
+}
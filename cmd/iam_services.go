@@ -0,0 +1,61 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+// iamServicesCmd represents the services command
+var iamServicesCmd = &cobra.Command{
+	Use:     "services",
+	Aliases: []string{"svc"},
+	Short:   "IAM Service identity management",
+	Long:    "IAM Service identity management.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+func init() {
+	iamCmd.AddCommand(iamServicesCmd)
+}
+
+// lookupService finds a service by service ID or UUID
+func lookupService(client *iam.Client, ref string) (*iam.Service, error) {
+	opts := &iam.GetServiceOptions{ServiceID: &ref}
+	if uuidPattern.MatchString(ref) {
+		opts = &iam.GetServiceOptions{ID: &ref}
+	}
+	service, _, err := client.Services.GetService(opts)
+	if errors.Is(err, iam.ErrEmptyResults) {
+		return nil, notFound("service '%s' not found", ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error looking up service '%s': %w", ref, err)
+	}
+	return service, nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+type keyRotation struct {
+	ServiceID      string   `json:"serviceId"`
+	KeyFile        string   `json:"keyFile"`
+	Fingerprint    string   `json:"fingerprint"`
+	ExpiresOn      string   `json:"expiresOn,omitempty"`
+	Scopes         []string `json:"scopes,omitempty"`
+	OldKeyRejected *bool    `json:"oldKeyRejected,omitempty"`
+}
+
+// iamServicesRotateKeyCmd represents the services rotate-key command
+var iamServicesRotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key <service>",
+	Short: "Rotate the key of a service identity",
+	Long: `Creates a new key pair for a service, identified by service ID or UUID,
+uploads its certificate to IAM, writes a key file for it and verifies the new
+key by logging in with it.
+
+The new key is written to <key-file>.new first and only moved to --key-file
+once IAM accepted its certificate. When the upload fails the new key is kept in
+<key-file>.new in case IAM did replace the certificate.
+
+IAM keeps a single certificate per service, so the current key stops working
+as soon as the new certificate is uploaded. Access tokens issued to the old key
+stay valid until they expire. Use --old-key-file to confirm the old key is
+rejected afterwards. When the workspace is logged in using a key of the service
+it is updated to use the new key.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keyFile, _ := cmd.Flags().GetString("key-file")
+		validity, _ := cmd.Flags().GetInt("validity")
+		oldKeyFile, _ := cmd.Flags().GetString("old-key-file")
+		yes, _ := cmd.Flags().GetBool("yes")
+		if validity <= 0 {
+			return invalidInput("validity must be > 0")
+		}
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return err
+		}
+		service, err := lookupService(iamClient, args[0])
+		if err != nil {
+			return err
+		}
+		var oldKey *Key
+		if oldKeyFile != "" {
+			if oldKey, err = keyArg([]string{oldKeyFile}); err != nil {
+				return err
+			}
+			if oldKey.ID != service.ServiceID {
+				return invalidInput("%s is a key for %s, not %s", oldKeyFile, oldKey.ID, service.ServiceID)
+			}
+		}
+		if err := confirm(fmt.Sprintf("Rotate the key of %s? The current key stops working immediately", service.ServiceID), yes); err != nil {
+			return err
+		}
+
+		key, updated, err := rotateServiceKey(iamClient, service, keyFile, validity,
+			currentWorkspace.IAMRegion, currentWorkspace.IAMEnvironment)
+		if key != nil {
+			// The certificate was replaced, keep the workspace session working
+			if stored, err := decodeKey([]byte(currentWorkspace.IAMServiceKey)); err == nil && stored.ID == service.ServiceID {
+				encoded, err := key.encode()
+				if err != nil {
+					return err
+				}
+				currentWorkspace.IAMServiceKey = encoded
+				if err := currentWorkspace.save(); err != nil {
+					return fmt.Errorf("failed to save workspace: %w", err)
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+		privateKey, err := key.privateKey()
+		if err != nil {
+			return err
+		}
+		fingerprint, err := keyFingerprint(privateKey)
+		if err != nil {
+			return err
+		}
+		result := keyRotation{
+			ServiceID:   service.ServiceID,
+			KeyFile:     keyFile,
+			Fingerprint: fingerprint,
+			ExpiresOn:   updated.ExpiresOn,
+		}
+
+		// The new certificate can take a moment to become active
+		verification, err := retry.DoWithData(func() (*keyVerification, error) {
+			return verifyKey(key)
+		}, retry.Attempts(5), retry.Delay(2*time.Second), retry.LastErrorOnly(true))
+		if err != nil {
+			return fmt.Errorf("new key written to %s but it failed verification: %w", keyFile, err)
+		}
+		result.Scopes = verification.Scopes
+		if oldKey != nil {
+			_, err := verifyKey(oldKey)
+			rejected := err != nil
+			result.OldKeyRejected = &rejected
+			if !rejected {
+				fmt.Fprintf(os.Stderr, "warning: the old key of %s can still login\n", service.ServiceID)
+			}
+		}
+		return printObject(result)
+	},
+}
+
+// rotateServiceKey creates a new key for service and uploads its certificate.
+// The key is written to keyFile.new before the upload and moved to keyFile
+// after it, so the only copy of the key that IAM accepts is never lost and an
+// existing keyFile stays intact when the upload fails. The key is returned
+// whenever the certificate was replaced, also along with an error.
+func rotateServiceKey(client *iam.Client, service *iam.Service, keyFile string, validity int, region, environment string) (*Key, *iam.Service, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating key: %w", err)
+	}
+	key := newServiceKey(service.ServiceID, region, environment, privateKey)
+	encoded, err := key.encode()
+	if err != nil {
+		return nil, nil, err
+	}
+	pending := keyFile + ".new"
+	if err := writeFileAtomic(pending, []byte(encoded), 0600); err != nil {
+		return nil, nil, fmt.Errorf("error writing the new key, the certificate of %s was not changed: %w", service.ServiceID, err)
+	}
+	updated, _, err := client.Services.UpdateServiceCertificate(*service, privateKey, func(cert *x509.Certificate) error {
+		cert.NotAfter = time.Now().AddDate(0, 0, validity)
+		return nil
+	})
+	if err == nil && updated == nil {
+		err = fmt.Errorf("unexpected response")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error updating certificate of %s, the new key is kept in %s in case it was replaced: %w",
+			service.ServiceID, pending, err)
+	}
+	if err := os.Rename(pending, keyFile); err != nil {
+		return &key, updated, fmt.Errorf("the certificate of %s was replaced, its key is in %s: %w", service.ServiceID, pending, err)
+	}
+	return &key, updated, nil
+}
+
+func init() {
+	iamServicesCmd.AddCommand(iamServicesRotateKeyCmd)
+
+	iamServicesRotateKeyCmd.Flags().StringP("key-file", "f", "", "The file to write the new key to")
+	iamServicesRotateKeyCmd.Flags().Int("validity", 365, "Validity of the new certificate in days")
+	iamServicesRotateKeyCmd.Flags().String("old-key-file", "", "The current key file, to confirm it is rejected after rotation")
+	iamServicesRotateKeyCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	_ = iamServicesRotateKeyCmd.MarkFlagRequired("key-file")
+}
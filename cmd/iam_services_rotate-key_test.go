@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dip-software/go-dip-api/iam"
+)
+
+// newFakeServiceServer serves certificate updates of service svc-uuid. The
+// key pending in keyFile.new at the time of each upload is recorded.
+func newFakeServiceServer(t *testing.T, keyFile string, fail bool) (*iam.Client, func() []*Key) {
+	var mu sync.Mutex
+	uploads := make([]*Key, 0)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize/identity/Service/svc-uuid/$update-certificate", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Certificate string `json:"certificate"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		block, _ := pem.Decode([]byte(body.Certificate))
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var pending *Key
+		if data, err := os.ReadFile(keyFile + ".new"); err == nil {
+			if key, err := decodeKey(data); err == nil {
+				if privateKey, err := key.privateKey(); err == nil && privateKey.PublicKey.Equal(cert.PublicKey.(*rsa.PublicKey)) {
+					pending = key
+				}
+			}
+		}
+		mu.Lock()
+		uploads = append(uploads, pending)
+		mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("/authorize/identity/Service", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"total": 1,
+			"entry": []iam.Service{{ID: "svc-uuid", ServiceID: "svc@example.com", ExpiresOn: "2027-10-18T00:00:00Z"}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client, err := iam.NewClient(http.DefaultClient, &iam.Config{
+		OAuth2ClientID: "client",
+		OAuth2Secret:   "secret",
+		IAMURL:         server.URL,
+		IDMURL:         server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetTokens("token", "refresh", "", time.Now().Add(time.Hour).Unix())
+	return client, func() []*Key {
+		mu.Lock()
+		defer mu.Unlock()
+		return uploads
+	}
+}
+
+func TestRotateServiceKey(t *testing.T) {
+	service := &iam.Service{ID: "svc-uuid", ServiceID: "svc@example.com"}
+	keyFile := filepath.Join(t.TempDir(), "svc.key")
+	if err := os.WriteFile(keyFile, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	client, uploads := newFakeServiceServer(t, keyFile, false)
+
+	key, updated, err := rotateServiceKey(client, service, keyFile, 30, "eu-west", "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ExpiresOn != "2027-10-18T00:00:00Z" || key.ID != "svc@example.com" || key.Region != "eu-west" {
+		t.Errorf("unexpected rotation result %+v %+v", updated, key)
+	}
+	if u := uploads(); len(u) != 1 || u[0] == nil || *u[0] != *key {
+		t.Error("expected the new key to be written before its certificate was uploaded")
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written, err := decodeKey(data); err != nil || *written != *key {
+		t.Errorf("expected the new key in %s, got %v", keyFile, err)
+	}
+	if info, err := os.Stat(keyFile); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected key file mode 0600, got %v %v", info.Mode().Perm(), err)
+	}
+	if _, err := os.Stat(keyFile + ".new"); !os.IsNotExist(err) {
+		t.Error("expected the pending key file to be moved")
+	}
+}
+
+func TestRotateServiceKeyFailures(t *testing.T) {
+	service := &iam.Service{ID: "svc-uuid", ServiceID: "svc@example.com"}
+	keyFile := filepath.Join(t.TempDir(), "svc.key")
+	if err := os.WriteFile(keyFile, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	client, uploads := newFakeServiceServer(t, keyFile, true)
+	if key, _, err := rotateServiceKey(client, service, keyFile, 30, "eu-west", "prod"); err == nil || key != nil {
+		t.Fatalf("expected the failed upload to be reported, got %v %v", key, err)
+	}
+	if data, _ := os.ReadFile(keyFile); string(data) != "old" {
+		t.Error("expected the current key file to stay intact")
+	}
+	if u := uploads(); len(u) != 1 || u[0] == nil {
+		t.Fatal("expected one upload of a written key")
+	}
+	if data, err := os.ReadFile(keyFile + ".new"); err != nil {
+		t.Error("expected the new key to be kept in case the certificate was replaced")
+	} else if kept, err := decodeKey(data); err != nil || *kept != *uploads()[0] {
+		t.Errorf("expected the uploaded key to be kept, got %v", err)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing", "svc.key")
+	client, uploads = newFakeServiceServer(t, missing, false)
+	if _, _, err := rotateServiceKey(client, service, missing, 30, "eu-west", "prod"); err == nil {
+		t.Error("expected an error for an unwritable key file")
+	}
+	if len(uploads()) != 0 {
+		t.Error("expected no certificate upload when the key cannot be written")
+	}
+}
@@ -24,9 +24,6 @@ THE SOFTWARE.
 
 import (
 	"fmt"
-	"net/http"
-
-	"github.com/dip-software/go-dip-api/iam"
 
 	"github.com/spf13/cobra"
 )
@@ -36,44 +33,25 @@ var iamTokenCmd = &cobra.Command{
 	Use:   "token",
 	Short: "Returns the active token",
 	Long:  `Returns the active token, refreshing or initating a login if needed.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		iamClient, err := iam.NewClient(http.DefaultClient, &iam.Config{
-			Region:         currentWorkspace.IAMRegion,
-			Environment:    currentWorkspace.IAMEnvironment,
-			OAuth2ClientID: clientID,
-			OAuth2Secret:   clientSecret,
-		})
-		if err != nil {
-			fmt.Printf("error initializing IAM client: %v\n", err)
-			return
-		}
-		iamClient.SetTokens(currentWorkspace.IAMAccessToken,
-			currentWorkspace.IAMRefreshToken,
-			currentWorkspace.IAMIDToken,
-			currentWorkspace.IAMAccessTokenExpires)
-		introspect, _, err := iamClient.Introspect()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		iamClient, err := getIAMClient(cmd)
 		if err != nil {
-			fmt.Printf("error performing introspect: %v\n", err)
-			return
-		}
-		if introspect.Expires > currentWorkspace.IAMAccessTokenExpires {
-			token, _ := iamClient.Token()
-			currentWorkspace.IAMAccessToken = token
-			currentWorkspace.IAMRefreshToken = iamClient.RefreshToken()
-			currentWorkspace.IAMAccessTokenExpires = iamClient.Expires()
-			_ = currentWorkspace.save()
+			return fmt.Errorf("error initializing IAM client: %w", err)
 		}
 		if len(args) == 0 {
 			token, _ := iamClient.Token()
 			fmt.Printf("%s\n", token)
-			return
+			return nil
 		}
 		switch args[0] {
 		case "id":
 			fmt.Printf("%s\n", iamClient.IDToken())
 		case "refresh":
 			fmt.Printf("%s\n", iamClient.RefreshToken())
+		default:
+			return invalidInput("unknown token type '%s', use 'id' or 'refresh'", args[0])
 		}
+		return nil
 	},
 }
 
@@ -22,7 +22,19 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dip-software/go-dip-api/iam"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // iamUsersCmd represents the users command
@@ -31,11 +43,322 @@ var iamUsersCmd = &cobra.Command{
 	Aliases: []string{"u"},
 	Short:   "Manage IAM users",
 	Long:    `Manages IAM users in your organization.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
 func init() {
 	iamCmd.AddCommand(iamUsersCmd)
 }
+
+// userSpec describes a user as given on the command line or in an input file
+type userSpec struct {
+	LoginID                       string `json:"loginId,omitempty" yaml:"loginId,omitempty"`
+	Email                         string `json:"email,omitempty" yaml:"email,omitempty"`
+	GivenName                     string `json:"givenName,omitempty" yaml:"givenName,omitempty"`
+	FamilyName                    string `json:"familyName,omitempty" yaml:"familyName,omitempty"`
+	MobilePhone                   string `json:"mobilePhone,omitempty" yaml:"mobilePhone,omitempty"`
+	PreferredLanguage             string `json:"preferredLanguage,omitempty" yaml:"preferredLanguage,omitempty"`
+	PreferredCommunicationChannel string `json:"preferredCommunicationChannel,omitempty" yaml:"preferredCommunicationChannel,omitempty"`
+	Organization                  string `json:"organization,omitempty" yaml:"organization,omitempty"`
+	Description                   string `json:"description,omitempty" yaml:"description,omitempty"`
+	Password                      string `json:"password,omitempty" yaml:"password,omitempty"`
+}
+
+// validate checks the fields IAM requires to create a user
+func (s userSpec) validate() error {
+	missing := make([]string, 0)
+	for name, value := range map[string]string{
+		"loginId":    s.LoginID,
+		"email":      s.Email,
+		"givenName":  s.GivenName,
+		"familyName": s.FamilyName,
+	} {
+		if value == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return invalidInput("user '%s' is missing required fields: %s", s.LoginID, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// person converts the spec to an IAM Person, defaulting to org as managing organization
+func (s userSpec) person(org string) iam.Person {
+	if s.Organization != "" {
+		org = s.Organization
+	}
+	person := iam.Person{
+		LoginID:      s.LoginID,
+		ResourceType: "Person",
+		Name: iam.Name{
+			Given:  s.GivenName,
+			Family: s.FamilyName,
+		},
+		Telecom: []iam.TelecomEntry{
+			{System: "email", Value: s.Email},
+		},
+		ManagingOrganization:          org,
+		PreferredLanguage:             s.PreferredLanguage,
+		PreferredCommunicationChannel: s.PreferredCommunicationChannel,
+		Description:                   s.Description,
+		Password:                      s.Password,
+	}
+	if s.MobilePhone != "" {
+		person.Telecom = append(person.Telecom, iam.TelecomEntry{System: "mobile", Value: s.MobilePhone})
+	}
+	return person
+}
+
+// applyTo copies the fields set in the spec onto an existing profile
+func (s userSpec) applyTo(profile *iam.Profile) {
+	if s.GivenName != "" {
+		profile.GivenName = s.GivenName
+	}
+	if s.FamilyName != "" {
+		profile.FamilyName = s.FamilyName
+	}
+	if s.Email != "" {
+		profile.Contact.EmailAddress = s.Email
+	}
+	if s.MobilePhone != "" {
+		profile.Contact.MobilePhone = s.MobilePhone
+	}
+	if s.PreferredLanguage != "" {
+		profile.PreferredLanguage = s.PreferredLanguage
+	}
+	if s.PreferredCommunicationChannel != "" {
+		profile.PreferredCommunicationChannel = s.PreferredCommunicationChannel
+	}
+}
+
+// userSpecFromUser converts an existing IAM user to a userSpec
+func userSpecFromUser(user *iam.User) userSpec {
+	return userSpec{
+		LoginID:                       user.LoginID,
+		Email:                         user.EmailAddress,
+		GivenName:                     user.Name.Given,
+		FamilyName:                    user.Name.Family,
+		MobilePhone:                   user.PhoneNumber,
+		PreferredLanguage:             user.PreferredLanguage,
+		PreferredCommunicationChannel: user.PreferredCommunicationChannel,
+		Organization:                  user.ManagingOrganization,
+	}
+}
+
+// profileChanges lists the profile fields set in desired which differ from current
+func profileChanges(current, desired userSpec) []string {
+	changes := make([]string, 0)
+	for _, c := range []struct {
+		name     string
+		old, new string
+	}{
+		{"email", current.Email, desired.Email},
+		{"givenName", current.GivenName, desired.GivenName},
+		{"familyName", current.FamilyName, desired.FamilyName},
+		{"mobilePhone", current.MobilePhone, desired.MobilePhone},
+		{"preferredLanguage", current.PreferredLanguage, desired.PreferredLanguage},
+		{"preferredCommunicationChannel", current.PreferredCommunicationChannel, desired.PreferredCommunicationChannel},
+	} {
+		if c.new != "" && c.new != c.old {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", c.name, c.old, c.new))
+		}
+	}
+	return changes
+}
+
+// userSpecColumns maps CSV column names to userSpec fields using the JSON tags
+func userSpecColumns() map[string]int {
+	columns := make(map[string]int)
+	t := reflect.TypeOf(userSpec{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		columns[strings.ToLower(name)] = i
+	}
+	return columns
+}
+
+// readUserSpecs reads users from a JSON, YAML or CSV file, "-" reads JSON or YAML from stdin.
+// JSON and YAML files may contain a single user or a list of users.
+func readUserSpecs(file string) ([]userSpec, error) {
+	return readUserSpecsMapped(file, nil)
+}
+
+// readUserSpecsMapped is readUserSpecs with a mapping of CSV column names to userSpec fields
+func readUserSpecsMapped(file string, mapping map[string]string) ([]userSpec, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, invalidInput("error reading %s: %w", file, err)
+	}
+	if strings.EqualFold(filepath.Ext(file), ".csv") {
+		return parseUserCSV(strings.NewReader(string(data)), mapping)
+	}
+	// YAML is a superset of JSON so this handles both
+	var specs []userSpec
+	if err := yaml.Unmarshal(data, &specs); err != nil {
+		var spec userSpec
+		if err := yaml.Unmarshal(data, &spec); err != nil {
+			return nil, invalidInput("error parsing %s: %w", file, err)
+		}
+		specs = []userSpec{spec}
+	}
+	return specs, nil
+}
+
+// parseUserCSV reads users from CSV with a header row naming the userSpec fields.
+// mapping renames CSV columns to userSpec fields, a mapping to "-" ignores the column.
+func parseUserCSV(r io.Reader, mapping map[string]string) ([]userSpec, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, invalidInput("error reading CSV header: %w", err)
+	}
+	columns := userSpecColumns()
+	fields := make([]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if mapped, ok := mapping[strings.ToLower(name)]; ok {
+			name = mapped
+		}
+		if name == "-" {
+			fields[i] = -1
+			continue
+		}
+		field, ok := columns[strings.ToLower(name)]
+		if !ok {
+			return nil, invalidInput("unknown CSV column '%s', use --map to map it to a user field", name)
+		}
+		fields[i] = field
+	}
+	specs := make([]userSpec, 0)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, invalidInput("error reading CSV line %d: %w", line, err)
+		}
+		var spec userSpec
+		v := reflect.ValueOf(&spec).Elem()
+		for i, value := range record {
+			if fields[i] >= 0 {
+				v.Field(fields[i]).SetString(strings.TrimSpace(value))
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// lookupUser finds a user by login ID or UUID
+func lookupUser(client *iam.Client, ref string) (*iam.User, error) {
+	user, _, err := client.Users.GetUserByID(ref)
+	if errors.Is(err, iam.ErrEmptyResults) || (err == nil && user == nil) {
+		return nil, notFound("user '%s' not found", ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error looking up user '%s': %w", ref, err)
+	}
+	return user, nil
+}
+
+// userRefs returns the users to act on from args or the --file flag
+func userRefs(cmd *cobra.Command, args []string) ([]string, error) {
+	refs := append([]string{}, args...)
+	if file, _ := cmd.Flags().GetString("file"); file != "" {
+		specs, err := readUserSpecs(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, spec := range specs {
+			if spec.LoginID == "" {
+				return nil, invalidInput("user entry without loginId in %s", file)
+			}
+			refs = append(refs, spec.LoginID)
+		}
+	}
+	if len(refs) == 0 {
+		return nil, invalidInput("specify one or more users as arguments or using --file")
+	}
+	return refs, nil
+}
+
+type userActionResult struct {
+	User   string `json:"user"`
+	ID     string `json:"id,omitempty"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// runUserAction applies action to each referenced user and reports the outcome per user.
+// Failures do not stop processing, the first error determines the exit code.
+func runUserAction(cmd *cobra.Command, refs []string, verb string, action func(client *iam.Client, user *iam.User) error) error {
+	iamClient, err := getIAMClient(cmd)
+	if err != nil {
+		return fmt.Errorf("error initalizing IAM client: %w", err)
+	}
+	results := make([]userActionResult, 0, len(refs))
+	var firstErr error
+	for _, ref := range refs {
+		result := userActionResult{User: ref, Result: verb}
+		user, err := lookupUser(iamClient, ref)
+		if err == nil {
+			result.ID = user.ID
+			err = action(iamClient, user)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			result.Result = "failed"
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return printUserResults(results, firstErr)
+}
+
+// printUserResults reports per user results and fails when any user failed
+func printUserResults(results []userActionResult, firstErr error) error {
+	rows := make([][]interface{}, 0, len(results))
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+		rows = append(rows, []interface{}{result.User, result.ID, result.Result, result.Error})
+	}
+	if err := printOutput(results, []string{"user", "id", "result", "error"}, rows); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return &cliError{kind: classifyError(firstErr), err: fmt.Errorf("%d of %d users failed", failed, len(results))}
+	}
+	return nil
+}
+
+// actionOK turns the boolean outcome of go-dip-api actions into an error
+func actionOK(ok bool, resp *iam.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if !ok {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode()
+		}
+		return statusError(status, "unexpected response status %d", status)
+	}
+	return nil
+}
@@ -35,11 +35,10 @@ var iamUsersChangeLoginCmd = &cobra.Command{
 	Aliases: []string{"cl"},
 	Short:   "Change login ID",
 	Long:    `Changes the login ID.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		iamClient, err := getIAMClient(cmd)
 		if err != nil {
-			fmt.Printf("error initalizing IAM client: %v\n", err)
-			return
+			return fmt.Errorf("error initalizing IAM client: %w", err)
 		}
 		opts := &iam.GetRolesOptions{}
 		if name, err := cmd.Flags().GetString("name"); err == nil && name != "" {
@@ -52,15 +51,17 @@ var iamUsersChangeLoginCmd = &cobra.Command{
 		newLogin, _ := cmd.Flags().GetString("new")
 		id, _, err := iamClient.Users.GetUserIDByLoginID(oldLogin)
 		if err != nil {
-			fmt.Printf("error retrieving user %s: %v\n", oldLogin, err)
-			return
+			return fmt.Errorf("error retrieving user %s: %w", oldLogin, err)
 		}
 		ok, _, err := iamClient.Users.ChangeLoginID(iam.Person{ID: id}, newLogin)
-		if err != nil || !ok {
-			fmt.Printf("error changing loginID: %v\n", err)
-			return
+		if err != nil {
+			return fmt.Errorf("error changing loginID: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("error changing loginID")
 		}
 		fmt.Printf("OK\n")
+		return nil
 	},
 }
 
@@ -0,0 +1,110 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// iamUsersCreateCmd represents the create command
+var iamUsersCreateCmd = &cobra.Command{
+	Use:     "create",
+	Aliases: []string{"c", "new"},
+	Short:   "Create users",
+	Long: `Creates one or more users in the selected organization.
+
+A single user can be specified using flags. Use --file to create users from a
+JSON, YAML or CSV file. CSV files need a header row with the column names
+loginId, email, givenName, familyName, mobilePhone, preferredLanguage,
+preferredCommunicationChannel, organization, description and password.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var specs []userSpec
+		if file, _ := cmd.Flags().GetString("file"); file != "" {
+			var err error
+			if specs, err = readUserSpecs(file); err != nil {
+				return err
+			}
+		} else {
+			var spec userSpec
+			spec.LoginID, _ = cmd.Flags().GetString("login-id")
+			spec.Email, _ = cmd.Flags().GetString("email")
+			spec.GivenName, _ = cmd.Flags().GetString("given-name")
+			spec.FamilyName, _ = cmd.Flags().GetString("family-name")
+			spec.MobilePhone, _ = cmd.Flags().GetString("mobile")
+			spec.PreferredLanguage, _ = cmd.Flags().GetString("language")
+			spec.Organization, _ = cmd.Flags().GetString("org")
+			spec.Description, _ = cmd.Flags().GetString("description")
+			specs = []userSpec{spec}
+		}
+		for _, spec := range specs {
+			if err := spec.validate(); err != nil {
+				return err
+			}
+		}
+		if currentWorkspace.IAMSelectedOrg == "" {
+			for _, spec := range specs {
+				if spec.Organization == "" {
+					return errNoOrgSelected
+				}
+			}
+		}
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+		results := make([]userActionResult, 0, len(specs))
+		var firstErr error
+		for _, spec := range specs {
+			result := userActionResult{User: spec.LoginID, Result: "created"}
+			user, _, err := iamClient.Users.CreateUser(spec.person(currentWorkspace.IAMSelectedOrg))
+			if err == nil && user == nil {
+				err = fmt.Errorf("user not returned after create")
+			}
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				result.Result = "failed"
+				result.Error = err.Error()
+			} else {
+				result.ID = user.ID
+			}
+			results = append(results, result)
+		}
+		return printUserResults(results, firstErr)
+	},
+}
+
+func init() {
+	iamUsersCmd.AddCommand(iamUsersCreateCmd)
+	iamUsersCreateCmd.Flags().String("login-id", "", "Login ID of the new user")
+	iamUsersCreateCmd.Flags().String("email", "", "Email address")
+	iamUsersCreateCmd.Flags().String("given-name", "", "Given name")
+	iamUsersCreateCmd.Flags().String("family-name", "", "Family name")
+	iamUsersCreateCmd.Flags().String("mobile", "", "Mobile phone number")
+	iamUsersCreateCmd.Flags().String("language", "", "Preferred language, e.g. en-US")
+	iamUsersCreateCmd.Flags().String("org", "", "Managing organization ID (default is the selected organization)")
+	iamUsersCreateCmd.Flags().String("description", "", "Description")
+	iamUsersCreateCmd.Flags().StringP("file", "f", "", "Create users from a JSON, YAML or CSV file")
+}
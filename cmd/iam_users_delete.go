@@ -0,0 +1,59 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+// iamUsersDeleteCmd represents the delete command
+var iamUsersDeleteCmd = &cobra.Command{
+	Use:     "delete [user...]",
+	Aliases: []string{"rm"},
+	Short:   "Delete users",
+	Long: `Deletes one or more users.
+
+Users are identified by login ID or UUID, use --file to read them from a
+JSON, YAML or CSV file.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refs, err := userRefs(cmd, args)
+		if err != nil {
+			return err
+		}
+		yes, _ := cmd.Flags().GetBool("yes")
+		if err := confirm(fmt.Sprintf("Delete %d user(s)", len(refs)), yes); err != nil {
+			return err
+		}
+		return runUserAction(cmd, refs, "deleted", func(client *iam.Client, user *iam.User) error {
+			return actionOK(client.Users.DeleteUser(iam.Person{ID: user.ID}))
+		})
+	},
+}
+
+func init() {
+	iamUsersCmd.AddCommand(iamUsersDeleteCmd)
+	iamUsersDeleteCmd.Flags().StringP("file", "f", "", "Read users from a JSON, YAML or CSV file")
+	iamUsersDeleteCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+}
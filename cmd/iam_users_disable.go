@@ -0,0 +1,63 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+// iamUsersDisableCmd represents the disable command
+var iamUsersDisableCmd = &cobra.Command{
+	Use:   "disable [user...]",
+	Short: "Disable users",
+	Long: `Disables one or more users, which prevents them from logging in.
+
+Users are identified by login ID or UUID, use --file to read them from a
+JSON, YAML or CSV file.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refs, err := userRefs(cmd, args)
+		if err != nil {
+			return err
+		}
+		return runUserAction(cmd, refs, "disabled", setUserDisabled(true))
+	},
+}
+
+func init() {
+	iamUsersCmd.AddCommand(iamUsersDisableCmd)
+	iamUsersDisableCmd.Flags().StringP("file", "f", "", "Read users from a JSON, YAML or CSV file")
+}
+
+// setUserDisabled returns a user action which sets the disabled state of the user profile
+func setUserDisabled(disabled bool) func(client *iam.Client, user *iam.User) error {
+	return func(client *iam.Client, user *iam.User) error {
+		profile, _, err := client.Users.LegacyGetUserByUUID(user.ID)
+		if err != nil {
+			return err
+		}
+		profile.ID = user.ID
+		profile.Disabled = &disabled
+		_, _, err = client.Users.LegacyUpdateUser(*profile)
+		return err
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// iamUsersEnableCmd represents the enable command
+var iamUsersEnableCmd = &cobra.Command{
+	Use:   "enable [user...]",
+	Short: "Enable users",
+	Long: `Enables one or more previously disabled users.
+
+Users are identified by login ID or UUID, use --file to read them from a
+JSON, YAML or CSV file.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refs, err := userRefs(cmd, args)
+		if err != nil {
+			return err
+		}
+		return runUserAction(cmd, refs, "enabled", setUserDisabled(false))
+	},
+}
+
+func init() {
+	iamUsersCmd.AddCommand(iamUsersEnableCmd)
+	iamUsersEnableCmd.Flags().StringP("file", "f", "", "Read users from a JSON, YAML or CSV file")
+}
@@ -0,0 +1,361 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+// iamUsersImportCmd represents the import command
+var iamUsersImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import users from CSV",
+	Long: `Imports users from a CSV, JSON or YAML file into the selected organization.
+
+Users which do not exist yet are created. Existing users are left alone unless
+--update is given, in which case their profile is updated to match the file.
+Use --dry-run to see what would change without changing anything.
+
+CSV columns are matched to user fields by name, use --map to map other column
+names, e.g. --map "E-Mail=email,First Name=givenName". Map a column to "-" to
+ignore it.
+
+Progress is recorded in a resume file next to the input file. When an import
+fails or is interrupted, running the same command again continues where it
+stopped and retries the failed rows. Use --restart to start over. Imports
+read from stdin cannot be resumed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := args[0]
+		mapping, _ := cmd.Flags().GetStringToString("map")
+		lowered := make(map[string]string, len(mapping))
+		for column, field := range mapping {
+			lowered[strings.ToLower(strings.TrimSpace(column))] = strings.TrimSpace(field)
+		}
+		rate, _ := cmd.Flags().GetFloat64("rate")
+		if rate <= 0 {
+			return invalidInput("--rate must be greater than 0")
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		update, _ := cmd.Flags().GetBool("update")
+		restart, _ := cmd.Flags().GetBool("restart")
+		errorsFile, _ := cmd.Flags().GetString("errors")
+		resumeFile, _ := cmd.Flags().GetString("resume")
+		if resumeFile != "" && file == "-" {
+			// The resume file is tied to a checksum of the input file
+			return invalidInput("--resume cannot be used when reading from stdin, save the input to a file first")
+		}
+		if resumeFile == "" && file != "-" {
+			resumeFile = file + ".progress"
+		}
+
+		specs, err := readUserSpecsMapped(file, lowered)
+		if err != nil {
+			return err
+		}
+		var progress *importProgress
+		if resumeFile != "" {
+			if progress, err = loadImportProgress(resumeFile, file, restart); err != nil {
+				return err
+			}
+		}
+		if currentWorkspace.IAMSelectedOrg == "" {
+			return errNoOrgSelected
+		}
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+
+		results := make([]importResult, 0, len(specs))
+		var firstErr error
+		resumed := 0
+		for i, spec := range specs {
+			row := i + 1
+			if progress != nil && progress.done(row) {
+				resumed++
+				continue
+			}
+			select {
+			case <-ctx.Done():
+			case <-ticker.C:
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			result := importUser(iamClient, row, spec, update, dryRun)
+			if result.err != nil {
+				if firstErr == nil {
+					firstErr = result.err
+				}
+			} else if progress != nil && !dryRun {
+				progress.Completed = append(progress.Completed, row)
+				if err := progress.save(resumeFile); err != nil {
+					return fmt.Errorf("error saving progress: %w", err)
+				}
+			}
+			results = append(results, result)
+		}
+
+		if err := printImportResults(results); err != nil {
+			return err
+		}
+		if errorsFile != "" {
+			if err := writeImportErrors(errorsFile, results); err != nil {
+				return err
+			}
+		}
+		failed := 0
+		counts := make(map[string]int)
+		for _, result := range results {
+			counts[result.Result]++
+			if result.err != nil {
+				failed++
+			}
+		}
+		summary := make([]string, 0, len(counts)+1)
+		if resumed > 0 {
+			summary = append(summary, fmt.Sprintf("%d already imported", resumed))
+		}
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[name], name))
+		}
+		fmt.Fprintf(os.Stderr, "%d rows: %s\n", len(specs), strings.Join(summary, ", "))
+
+		if ctx.Err() != nil {
+			return newError(kindError, "import interrupted after %d of %d rows, run the same command again to resume", resumed+len(results), len(specs))
+		}
+		if failed > 0 {
+			err := fmt.Errorf("%d of %d rows failed", failed, len(specs))
+			if progress != nil && !dryRun {
+				err = fmt.Errorf("%w, fix them and run the same command again to retry", err)
+			}
+			return &cliError{kind: classifyError(firstErr), err: err}
+		}
+		if progress != nil && !dryRun {
+			if err := os.Remove(resumeFile); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+type importResult struct {
+	Row     int      `json:"row"`
+	User    string   `json:"user"`
+	ID      string   `json:"id,omitempty"`
+	Result  string   `json:"result"`
+	Changes []string `json:"changes,omitempty"`
+	Error   string   `json:"error,omitempty"`
+	err     error
+}
+
+// importUser creates or updates the user of a single row. In dry run mode it
+// only reports what would be done.
+func importUser(client *iam.Client, row int, spec userSpec, update, dryRun bool) importResult {
+	result := importResult{Row: row, User: spec.LoginID}
+	fail := func(err error) importResult {
+		result.Result = "failed"
+		result.Error = err.Error()
+		result.err = err
+		return result
+	}
+	if spec.LoginID == "" {
+		return fail(invalidInput("row %d has no loginId", row))
+	}
+	user, err := lookupUser(client, spec.LoginID)
+	if err != nil && classifyError(err) != kindNotFound {
+		return fail(err)
+	}
+	if user == nil {
+		if err := spec.validate(); err != nil {
+			return fail(err)
+		}
+		if dryRun {
+			result.Result = "would create"
+			return result
+		}
+		created, _, err := client.Users.CreateUser(spec.person(currentWorkspace.IAMSelectedOrg))
+		if err == nil && created == nil {
+			err = fmt.Errorf("user not returned after create")
+		}
+		if err != nil {
+			return fail(err)
+		}
+		result.ID = created.ID
+		result.Result = "created"
+		return result
+	}
+
+	result.ID = user.ID
+	result.Changes = profileChanges(userSpecFromUser(user), spec)
+	switch {
+	case len(result.Changes) == 0:
+		result.Result = "unchanged"
+	case !update:
+		result.Result = "skipped"
+	case dryRun:
+		result.Result = "would update"
+	default:
+		profile, _, err := client.Users.LegacyGetUserByUUID(user.ID)
+		if err != nil {
+			return fail(err)
+		}
+		profile.ID = user.ID
+		spec.applyTo(profile)
+		if _, _, err := client.Users.LegacyUpdateUser(*profile); err != nil {
+			return fail(err)
+		}
+		result.Result = "updated"
+	}
+	return result
+}
+
+func printImportResults(results []importResult) error {
+	rows := make([][]interface{}, 0, len(results))
+	for _, result := range results {
+		detail := result.Error
+		if detail == "" {
+			detail = strings.Join(result.Changes, ", ")
+		}
+		rows = append(rows, []interface{}{result.Row, result.User, result.Result, detail})
+	}
+	return printOutput(results, []string{"row", "user", "result", "details"}, rows)
+}
+
+// writeImportErrors writes the failed rows to a CSV report
+func writeImportErrors(file string, results []importResult) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	_ = w.Write([]string{"row", "loginId", "error"})
+	for _, result := range results {
+		if result.err != nil {
+			_ = w.Write([]string{strconv.Itoa(result.Row), result.User, result.Error})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// importProgress records which rows of an input file were imported successfully
+type importProgress struct {
+	Source    string `json:"source"`
+	Checksum  string `json:"checksum"`
+	Completed []int  `json:"completed"`
+
+	completed map[int]bool
+}
+
+// loadImportProgress reads the progress of an earlier import of source. A
+// progress file belonging to a different version of source is an error unless
+// restart is set.
+func loadImportProgress(file, source string, restart bool) (*importProgress, error) {
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	fresh := &importProgress{Source: source, Checksum: hex.EncodeToString(sum[:]), Completed: []int{}}
+	if restart {
+		return fresh, nil
+	}
+	data, err = os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return fresh, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var progress importProgress
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return nil, invalidInput("corrupt resume file %s: %v, use --restart to start over", file, err)
+	}
+	if progress.Checksum != fresh.Checksum {
+		return nil, invalidInput("%s changed since the import recorded in %s started, use --restart to start over", source, file)
+	}
+	if len(progress.Completed) > 0 {
+		fmt.Fprintf(os.Stderr, "resuming import, %d rows already done\n", len(progress.Completed))
+	}
+	return &progress, nil
+}
+
+func (p *importProgress) done(row int) bool {
+	if p.completed == nil {
+		p.completed = make(map[int]bool, len(p.Completed))
+		for _, r := range p.Completed {
+			p.completed[r] = true
+		}
+	}
+	return p.completed[row]
+}
+
+func (p *importProgress) save(file string) error {
+	sort.Ints(p.Completed)
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(file, data, 0600)
+}
+
+func init() {
+	iamUsersCmd.AddCommand(iamUsersImportCmd)
+	iamUsersImportCmd.Flags().StringToStringP("map", "m", nil, "Map CSV column names to user fields, e.g. \"E-Mail=email\"")
+	iamUsersImportCmd.Flags().Bool("dry-run", false, "Show what would be created or updated without changing anything")
+	iamUsersImportCmd.Flags().Bool("update", false, "Update the profile of existing users")
+	iamUsersImportCmd.Flags().Float64("rate", 5, "Maximum number of rows to process per second")
+	iamUsersImportCmd.Flags().String("errors", "", "Write failed rows with their error to this CSV file")
+	iamUsersImportCmd.Flags().String("resume", "", "Resume file to record progress in (default <file>.progress)")
+	iamUsersImportCmd.Flags().Bool("restart", false, "Ignore an existing resume file and start over")
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseUserCSVMapping(t *testing.T) {
+	input := "User,E-Mail,First,Last,Shoe Size\nalice,alice@example.com,Alice,Doe,42\n"
+	mapping := map[string]string{"user": "loginId", "e-mail": "email", "first": "givenName", "last": "familyName", "shoe size": "-"}
+	specs, err := parseUserCSV(strings.NewReader(input), mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) != 1 || specs[0].LoginID != "alice" || specs[0].Email != "alice@example.com" || specs[0].GivenName != "Alice" {
+		t.Errorf("unexpected users %+v", specs)
+	}
+}
+
+func TestProfileChanges(t *testing.T) {
+	current := userSpec{LoginID: "alice", Email: "alice@example.com", GivenName: "Alice", FamilyName: "Doe"}
+	if changes := profileChanges(current, userSpec{LoginID: "alice", Email: "alice@example.com"}); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+	changes := profileChanges(current, userSpec{LoginID: "alice", FamilyName: "Smith"})
+	if len(changes) != 1 || changes[0] != "familyName: Doe -> Smith" {
+		t.Errorf("unexpected changes %v", changes)
+	}
+}
+
+func TestImportProgressResume(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "users.csv")
+	resume := source + ".progress"
+	if err := os.WriteFile(source, []byte("loginId\nalice\nbob\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	progress, err := loadImportProgress(resume, source, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	progress.Completed = append(progress.Completed, 2)
+	if err := progress.save(resume); err != nil {
+		t.Fatal(err)
+	}
+
+	progress, err = loadImportProgress(resume, source, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.done(1) || !progress.done(2) {
+		t.Errorf("expected only row 2 to be done, got %v", progress.Completed)
+	}
+
+	if err := os.WriteFile(source, []byte("loginId\ncarol\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadImportProgress(resume, source, false); err == nil {
+		t.Error("expected error when the source file changed")
+	}
+	progress, err = loadImportProgress(resume, source, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress.done(2) {
+		t.Error("expected restart to discard progress")
+	}
+}
+
+func TestExportFieldsRejectsPassword(t *testing.T) {
+	if _, err := exportFields([]string{"loginId", "Email"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, column := range []string{"password", "Password", "PASSWORD", "shoeSize"} {
+		if _, err := exportFields([]string{"loginId", column}); err == nil {
+			t.Errorf("expected column %s to be rejected", column)
+		}
+	}
+}
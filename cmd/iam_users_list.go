@@ -22,116 +22,255 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
-	"github.com/cheynewallace/tabby"
 	"github.com/dip-software/go-dip-api/iam"
 	"github.com/spf13/cobra"
 )
 
+// userLookupWorkers bounds the number of concurrent user detail lookups
+const userLookupWorkers = 10
+
 // iamUsersListCmd represents the list command
 var iamUsersListCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"l", "ls"},
 	Short:   "List users",
-	Long:    `Lists users in the selected organization.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Lists users in the selected organization.
+
+By default the first 50 users are shown, use --limit to change this
+or --all to page through every user in the organization.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		iamClient, err := getIAMClient(cmd)
 		if err != nil {
-			fmt.Printf("error initalizing IAM client: %v\n", err)
-			return
+			return fmt.Errorf("error initalizing IAM client: %w", err)
 		}
 		if currentWorkspace.IAMSelectedOrg == "" {
-			fmt.Printf("please select an organization first using: hs iam orgs select\n")
-			return
+			return errNoOrgSelected
 		}
-		pageSize := "50" // TODO: implement paging
-		users, _, err := iamClient.Users.GetUsers(&iam.GetUserOptions{
+		pageSize, _ := cmd.Flags().GetInt("page-size")
+		limit, _ := cmd.Flags().GetInt("limit")
+		all, _ := cmd.Flags().GetBool("all")
+		if pageSize < 1 {
+			return invalidInput("--page-size must be at least 1")
+		}
+		if all {
+			if cmd.Flags().Changed("limit") {
+				return invalidInput("--all and --limit are mutually exclusive")
+			}
+			limit = 0
+		} else if limit < 1 {
+			return invalidInput("--limit must be at least 1, use --all to list every user")
+		}
+
+		opts := iam.GetUserOptions{
 			OrganizationID: &currentWorkspace.IAMSelectedOrg,
-			PageSize:       &pageSize,
-		})
-		if err != nil {
-			fmt.Printf("error performing IAM introspect: %v\n", err)
-			return
 		}
-		if !jsonOut {
-			fmt.Printf("Users in Organization: %s\n\n", currentWorkspace.IAMSelectedOrgName)
+		if loginID, _ := cmd.Flags().GetString("login-id"); loginID != "" {
+			opts.LoginID = &loginID
 		}
-		if len(users.UserUUIDs) == 0 {
-			if jsonOut {
-				fmt.Printf("[]\n")
-				return
-			}
-			fmt.Printf("no users found or not enough permissions\n")
-			return
-		}
-		numWorkers := 10
-		numUsers := len(users.UserUUIDs)
-		if numWorkers > numUsers {
-			numWorkers = numUsers
-		}
-		done := make(chan bool)
-		queue := make(chan string)
-		result := make(chan *iam.User)
-		// Start workers
-		for i := 0; i < numWorkers; i++ {
-			go fetchUser(iamClient, i, queue, result, done)
-		}
-		// Fill queue
-		go func() {
-			for _, uuid := range users.UserUUIDs {
-				queue <- uuid
+		if group, _ := cmd.Flags().GetString("group"); group != "" {
+			groupID, err := resolveGroupID(iamClient, currentWorkspace.IAMSelectedOrg, group)
+			if err != nil {
+				return err
 			}
-		}()
+			opts.GroupID = &groupID
+		}
+		var filter userFilter
+		filter.email, _ = cmd.Flags().GetString("email")
+		if cmd.Flags().Changed("disabled") {
+			disabled, _ := cmd.Flags().GetBool("disabled")
+			filter.disabled = &disabled
+		}
 
-		if jsonOut {
-			fmt.Printf("[")
-			for i := 0; i < numUsers; i++ {
-				user := <-result
-				data, _ := json.Marshal(user)
-				fmt.Printf("%s", string(data))
-				if i < numUsers-1 { // Last one
-					fmt.Printf(",")
-				}
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		users, err := listUsers(ctx, iamClient, opts, pageSize, limit, filter)
+		if err != nil {
+			return err
+		}
+		if tableOutput() {
+			fmt.Printf("Users in Organization: %s\n\n", currentWorkspace.IAMSelectedOrgName)
+			if len(users) == 0 {
+				fmt.Printf("no users found or not enough permissions\n")
+				return nil
 			}
-			fmt.Printf("]\n")
-			return
 		}
-		t := tabby.New()
-		t.AddHeader("loginID", "first name", "last name", "email")
-		for i := 0; i < numUsers; i++ {
-			user := <-result
-			t.AddLine(user.LoginID, user.Name.Given, user.Name.Family, user.EmailAddress)
+		rows := make([][]interface{}, 0, len(users))
+		for _, user := range users {
+			rows = append(rows, []interface{}{user.LoginID, user.Name.Given, user.Name.Family, user.EmailAddress})
 		}
-		t.Print()
-		// Clean up
-		for i := 0; i < numWorkers; i++ {
-			done <- true
+		if err := printOutput(users, []string{"loginID", "first name", "last name", "email"}, rows); err != nil {
+			return err
 		}
-		_ = currentWorkspace.saveWithIAM(iamClient)
+		if limit > 0 && len(users) == limit {
+			fmt.Fprintf(os.Stderr, "showing the first %d users, more may exist: use --limit or --all\n", limit)
+		}
+		return nil
 	},
 }
 
-func fetchUser(client *iam.Client, _ int, queue chan string, result chan *iam.User, done chan bool) {
-	errorUser := &iam.User{
-		ID: "error",
+// userFilter holds the filters IAM cannot apply server side
+type userFilter struct {
+	email    string
+	disabled *bool
+}
+
+func (f userFilter) match(user *iam.User) bool {
+	if f.email != "" && !strings.Contains(strings.ToLower(user.EmailAddress), strings.ToLower(f.email)) {
+		return false
+	}
+	if f.disabled != nil && user.AccountStatus.Disabled != *f.disabled {
+		return false
+	}
+	return true
+}
+
+func (f userFilter) empty() bool {
+	return f.email == "" && f.disabled == nil
+}
+
+// userResult is the lookup of the user at index in the listing order
+type userResult struct {
+	index int
+	uuid  string
+	user  *iam.User
+	err   error
+}
+
+// listUsers pages through the users matching opts and looks up their details
+// using userLookupWorkers concurrent requests. Lookups are collected in the
+// order IAM returned the users, so the limit always keeps the first users
+// matching filter, a limit of 0 returns all users. Cancelling ctx aborts the listing.
+func listUsers(ctx context.Context, client *iam.Client, opts iam.GetUserOptions, pageSize, limit int, filter userFilter) ([]*iam.User, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	uuids := make(chan userResult)
+	results := make(chan userResult)
+	var pageErr error
+
+	// Pager
+	go func() {
+		defer close(uuids)
+		size := strconv.Itoa(pageSize)
+		opts.PageSize = &size
+		produced := 0
+		for page := 1; ; page++ {
+			number := strconv.Itoa(page)
+			opts.PageNumber = &number
+			list, _, err := client.Users.GetUsers(&opts)
+			if err != nil {
+				pageErr = fmt.Errorf("error retrieving users page %d: %w", page, err)
+				return
+			}
+			for _, uuid := range list.UserUUIDs {
+				select {
+				case uuids <- userResult{index: produced, uuid: uuid}:
+					produced++
+				case <-ctx.Done():
+					return
+				}
+			}
+			if !list.HasNextPage || len(list.UserUUIDs) == 0 {
+				return
+			}
+			if limit > 0 && filter.empty() && produced >= limit {
+				return
+			}
+		}
+	}()
+
+	// Lookup workers
+	var wg sync.WaitGroup
+	for i := 0; i < userLookupWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var result userResult
+				var ok bool
+				select {
+				case result, ok = <-uuids:
+					if !ok {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+				user, _, err := client.Users.GetUserByID(result.uuid)
+				if err == nil && user == nil {
+					err = notFound("user %s not found", result.uuid)
+				}
+				result.user, result.err = user, err
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
 	}
-	for {
-		select {
-		case id := <-queue:
-			user, _, _ := client.Users.GetUserByID(id)
-			if user != nil {
-				result <- user
-			} else {
-				result <- errorUser
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Lookups finish in any order, hold them back until all earlier ones arrived
+	users := make([]*iam.User, 0)
+	pending := make(map[int]userResult)
+	next := 0
+collect:
+	for result := range results {
+		pending[result.index] = result
+		for {
+			result, ok := pending[next]
+			if !ok {
+				break
 			}
-		case <-done:
-			return
+			delete(pending, next)
+			next++
+			if result.err != nil {
+				return nil, fmt.Errorf("error retrieving user %s: %w", result.uuid, result.err)
+			}
+			if !filter.match(result.user) {
+				continue
+			}
+			users = append(users, result.user)
+			if limit > 0 && len(users) >= limit {
+				break collect
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("listing users interrupted: %w", err)
+	}
+	// pageErr is only safe to read once all workers finished, i.e. the limit was not reached
+	if limit == 0 || len(users) < limit {
+		if pageErr != nil {
+			return nil, pageErr
 		}
 	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].LoginID < users[j].LoginID
+	})
+	return users, nil
 }
 
 func init() {
 	iamUsersCmd.AddCommand(iamUsersListCmd)
+	iamUsersListCmd.Flags().Int("limit", 50, "Maximum number of users to list")
+	iamUsersListCmd.Flags().Int("page-size", 50, "Number of users to request per page")
+	iamUsersListCmd.Flags().Bool("all", false, "List all users, paging through the complete result")
+	iamUsersListCmd.Flags().String("login-id", "", "Filter by login ID")
+	iamUsersListCmd.Flags().String("email", "", "Filter by email address (case insensitive substring match)")
+	iamUsersListCmd.Flags().String("group", "", "Filter by membership of group (name or ID)")
+	iamUsersListCmd.Flags().Bool("disabled", false, "Filter by disabled state, use --disabled=false for enabled users only")
 }
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dip-software/go-dip-api/iam"
+)
+
+// newFakeUsersServer serves total users in pages through the IDM endpoints used by listUsers.
+// A non-nil delay slows down the lookup of individual users.
+func newFakeUsersServer(t *testing.T, total int, delay func(n int) time.Duration) *iam.Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/security/users", func(w http.ResponseWriter, r *http.Request) {
+		size, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("pageNumber"))
+		type entry struct {
+			UserUUID string `json:"userUUID"`
+		}
+		users := make([]entry, 0)
+		for i := (page - 1) * size; i < page*size && i < total; i++ {
+			users = append(users, entry{fmt.Sprintf("uuid-%03d", i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"exchange": map[string]interface{}{
+				"users":          users,
+				"nextPageExists": page*size < total,
+			},
+		})
+	})
+	mux.HandleFunc("/authorize/identity/User", func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("userId")
+		var n int
+		_, _ = fmt.Sscanf(id, "uuid-%d", &n)
+		if delay != nil {
+			time.Sleep(delay(n))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"total": 1,
+			"entry": []iam.User{{
+				ID:            id,
+				LoginID:       fmt.Sprintf("user%03d", n),
+				EmailAddress:  fmt.Sprintf("user%03d@example.com", n),
+				AccountStatus: iam.UserAccountStatus{Disabled: n%2 == 1},
+			}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	client, err := iam.NewClient(http.DefaultClient, &iam.Config{
+		OAuth2ClientID: "client",
+		OAuth2Secret:   "secret",
+		IAMURL:         server.URL,
+		IDMURL:         server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetTokens("token", "refresh", "", time.Now().Add(time.Hour).Unix())
+	return client
+}
+
+func TestListUsersPaging(t *testing.T) {
+	client := newFakeUsersServer(t, 125, nil)
+	org := "org"
+	opts := iam.GetUserOptions{OrganizationID: &org}
+
+	users, err := listUsers(context.Background(), client, opts, 20, 0, userFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 125 {
+		t.Fatalf("expected all 125 users, got %d", len(users))
+	}
+	if users[0].LoginID != "user000" || users[124].LoginID != "user124" {
+		t.Errorf("expected users sorted by login ID, got %s..%s", users[0].LoginID, users[124].LoginID)
+	}
+
+	users, err = listUsers(context.Background(), client, opts, 20, 30, userFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 30 {
+		t.Errorf("expected limit of 30 users, got %d", len(users))
+	}
+
+	disabled := true
+	users, err = listUsers(context.Background(), client, opts, 20, 10, userFilter{disabled: &disabled, email: "USER1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 10 {
+		t.Fatalf("expected 10 filtered users, got %d", len(users))
+	}
+	for _, user := range users {
+		if !user.AccountStatus.Disabled {
+			t.Errorf("user %s is not disabled", user.LoginID)
+		}
+	}
+}
+
+func TestListUsersCancelled(t *testing.T) {
+	client := newFakeUsersServer(t, 100, nil)
+	org := "org"
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := listUsers(ctx, client, iam.GetUserOptions{OrganizationID: &org}, 10, 0, userFilter{}); err == nil {
+		t.Error("expected error for cancelled listing")
+	}
+}
+
+func TestListUsersLimitKeepsListingOrder(t *testing.T) {
+	// The first users are slow to look up so later ones finish first
+	client := newFakeUsersServer(t, 40, func(n int) time.Duration {
+		if n < 4 {
+			return 100 * time.Millisecond
+		}
+		return 0
+	})
+	org := "org"
+	users, err := listUsers(context.Background(), client, iam.GetUserOptions{OrganizationID: &org}, 40, 10, userFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 10 {
+		t.Fatalf("expected 10 users, got %d", len(users))
+	}
+	for i, user := range users {
+		if expected := fmt.Sprintf("user%03d", i); user.LoginID != expected {
+			t.Errorf("expected %s at %d, got %s", expected, i, user.LoginID)
+		}
+	}
+}
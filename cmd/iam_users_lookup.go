@@ -22,7 +22,6 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -34,33 +33,27 @@ var iamUsersLookupCmd = &cobra.Command{
 	Aliases: []string{"look"},
 	Short:   "Lookup user",
 	Long:    `Looks up a user based on a GUID.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		iamClient, err := getIAMClient(cmd)
 		if err != nil {
-			fmt.Printf("error initalizing IAM client: %v\n", err)
-			return
+			return fmt.Errorf("error initalizing IAM client: %w", err)
 		}
 		if currentWorkspace.IAMSelectedOrg == "" {
-			fmt.Printf("please select an organization first using: hs iam orgs select\n")
-			return
+			return errNoOrgSelected
 		}
 		if len(args) == 0 {
-			fmt.Printf("please specify the GUID as only argument\n")
-			return
+			return invalidInput("please specify the GUID as only argument")
 		}
 		guid := args[0]
 
 		user, _, err := iamClient.Users.LegacyGetUserByUUID(guid)
 		if err != nil {
-			fmt.Printf("error performing IAM introspect: %v\n", err)
-			return
+			return fmt.Errorf("error retrieving user %s: %w", guid, err)
 		}
-		if err != nil || user == nil {
-			fmt.Printf("{}\n")
-			return
+		if user == nil {
+			return notFound("user %s not found", guid)
 		}
-		data, _ := json.Marshal(user)
-		fmt.Printf("%s\n", pretty(data))
+		return printObject(user)
 	},
 }
 
@@ -0,0 +1,66 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+// iamUsersResetPasswordCmd represents the reset-password command
+var iamUsersResetPasswordCmd = &cobra.Command{
+	Use:     "reset-password [user...]",
+	Aliases: []string{"rp"},
+	Short:   "Send password reset emails",
+	Long: `Starts the password recovery flow for one or more users, sending them an email to set a new password.
+
+Users are identified by login ID or UUID, use --file to read them from a
+JSON, YAML or CSV file.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refs, err := userRefs(cmd, args)
+		if err != nil {
+			return err
+		}
+		return runUserAction(cmd, refs, "reset sent", recoverPassword)
+	},
+}
+
+func init() {
+	iamUsersCmd.AddCommand(iamUsersResetPasswordCmd)
+	iamUsersResetPasswordCmd.Flags().StringP("file", "f", "", "Read users from a JSON, YAML or CSV file")
+}
+
+// recoverPassword starts the IAM password recovery flow, which go-dip-api does not expose
+func recoverPassword(client *iam.Client, user *iam.User) error {
+	body := iam.Parameters{
+		ResourceType: "Parameters",
+		Parameter: []iam.Param{
+			{
+				Name:     "recoverPassword",
+				Resource: iam.Resource{LoginID: user.LoginID},
+			},
+		},
+	}
+	return iamIDMRequest(client, http.MethodPost, "authorize/identity/User/$recover-password", "1", body, nil)
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dip-software/go-dip-api/iam"
+)
+
+func TestReadUserSpecs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"single.json": `{"loginId":"alice","email":"alice@example.com","givenName":"Alice","familyName":"Doe"}`,
+		"list.yaml": `- loginId: alice
+  email: alice@example.com
+  givenName: Alice
+  familyName: Doe
+- loginId: bob
+  email: bob@example.com
+  givenName: Bob
+  familyName: Doe
+`,
+		"users.csv": "loginId, Email,givenName,familyName\nalice,alice@example.com,Alice,Doe\nbob,bob@example.com,Bob,Doe\n",
+	}
+	expected := map[string]int{"single.json": 1, "list.yaml": 2, "users.csv": 2}
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		specs, err := readUserSpecs(file)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(specs) != expected[name] {
+			t.Fatalf("%s: expected %d users, got %d", name, expected[name], len(specs))
+		}
+		if specs[0].LoginID != "alice" || specs[0].Email != "alice@example.com" || specs[0].FamilyName != "Doe" {
+			t.Errorf("%s: unexpected first user %+v", name, specs[0])
+		}
+		if err := specs[0].validate(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestParseUserCSVUnknownColumn(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "users.csv")
+	if err := os.WriteFile(file, []byte("loginId,shoeSize\nalice,42\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readUserSpecs(file); err == nil {
+		t.Error("expected error for unknown column")
+	}
+}
+
+func TestUserSpecValidate(t *testing.T) {
+	err := userSpec{LoginID: "alice"}.validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if classifyError(err) != kindInvalidInput {
+		t.Errorf("expected invalid input, got %s", classifyError(err))
+	}
+}
+
+func TestUpdateUsersAppliesSpecsByUserID(t *testing.T) {
+	// Users can be referenced by login ID, email address or UUID
+	ids := map[string]string{
+		"alice": "uuid-a", "uuid-a": "uuid-a",
+		"bob@example.com": "uuid-b",
+		"uuid-c":          "uuid-c",
+	}
+	var mu sync.Mutex
+	updated := make(map[string]string)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize/identity/User", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		id, ok := ids[r.URL.Query().Get("userId")]
+		if !ok {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"total": 0, "entry": []iam.User{}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"total": 1,
+			"entry": []iam.User{{ID: id, LoginID: "login-" + id}},
+		})
+	})
+	mux.HandleFunc("/security/users/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/security/users/")
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPut {
+			var profile iam.Profile
+			_ = json.NewDecoder(r.Body).Decode(&profile)
+			mu.Lock()
+			updated[id] = profile.GivenName
+			mu.Unlock()
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"exchange":     map[string]interface{}{"profile": iam.Profile{GivenName: "old"}},
+			"responseCode": "200",
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client, err := iam.NewClient(http.DefaultClient, &iam.Config{
+		OAuth2ClientID: "client",
+		OAuth2Secret:   "secret",
+		IAMURL:         server.URL,
+		IDMURL:         server.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetTokens("token", "refresh", "", time.Now().Add(time.Hour).Unix())
+
+	results, err := updateUsers(client, []userUpdate{
+		{ref: "alice", spec: userSpec{GivenName: "Alice"}},
+		{ref: "bob@example.com", spec: userSpec{GivenName: "Bob"}},
+		{ref: "uuid-c", spec: userSpec{GivenName: "Carol"}},
+		{ref: "nobody", spec: userSpec{GivenName: "Nobody"}},
+		{ref: "uuid-a", spec: userSpec{GivenName: "Again"}},
+	})
+	if err == nil {
+		t.Error("expected an error for the unknown and duplicate users")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	expected := map[string]string{"uuid-a": "Alice", "uuid-b": "Bob", "uuid-c": "Carol"}
+	if len(updated) != len(expected) {
+		t.Errorf("expected %d updates, got %v", len(expected), updated)
+	}
+	for id, name := range expected {
+		if updated[id] != name {
+			t.Errorf("expected %s to be updated to %s, got %q", id, name, updated[id])
+		}
+	}
+	for i, result := range []string{"updated", "updated", "updated", "failed", "failed"} {
+		if results[i].Result != result {
+			t.Errorf("%s: expected %s, got %s (%s)", results[i].User, result, results[i].Result, results[i].Error)
+		}
+	}
+}
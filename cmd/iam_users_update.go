@@ -0,0 +1,148 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+// iamUsersUpdateCmd represents the update command
+var iamUsersUpdateCmd = &cobra.Command{
+	Use:     "update [user]",
+	Aliases: []string{"u"},
+	Short:   "Update user profiles",
+	Long: `Updates the profile of a user, identified by login ID or UUID.
+
+Only the fields which are given are changed. Use --file to update multiple
+users from a JSON, YAML or CSV file, each entry identified by its loginId.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		updates := make([]userUpdate, 0)
+		if file, _ := cmd.Flags().GetString("file"); file != "" {
+			list, err := readUserSpecs(file)
+			if err != nil {
+				return err
+			}
+			for _, spec := range list {
+				if spec.LoginID == "" {
+					return invalidInput("user entry without loginId in %s", file)
+				}
+				updates = append(updates, userUpdate{ref: spec.LoginID, spec: spec})
+			}
+		}
+		if len(args) > 0 {
+			var spec userSpec
+			spec.Email, _ = cmd.Flags().GetString("email")
+			spec.GivenName, _ = cmd.Flags().GetString("given-name")
+			spec.FamilyName, _ = cmd.Flags().GetString("family-name")
+			spec.MobilePhone, _ = cmd.Flags().GetString("mobile")
+			spec.PreferredLanguage, _ = cmd.Flags().GetString("language")
+			if spec == (userSpec{}) {
+				return invalidInput("nothing to update, specify at least one field to change")
+			}
+			for _, ref := range args {
+				updates = append(updates, userUpdate{ref: ref, spec: spec})
+			}
+		}
+		if len(updates) == 0 {
+			return cmd.Help()
+		}
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+		results, firstErr := updateUsers(iamClient, updates)
+		return printUserResults(results, firstErr)
+	},
+}
+
+// userUpdate is a profile change for the user identified by ref
+type userUpdate struct {
+	ref  string
+	spec userSpec
+}
+
+// updateUsers resolves all referenced users to their IDs before changing any
+// profile, so each change is applied to the user it was given for whether it
+// is referenced by login ID, email address or UUID. Failures do not stop
+// processing, the first error is returned along with the per user results.
+func updateUsers(client *iam.Client, updates []userUpdate) ([]userActionResult, error) {
+	results := make([]userActionResult, len(updates))
+	ids := make([]string, len(updates))
+	specs := make(map[string]userSpec)
+	var firstErr error
+	fail := func(i int, err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+		results[i].Result = "failed"
+		results[i].Error = err.Error()
+	}
+	for i, update := range updates {
+		results[i] = userActionResult{User: update.ref, Result: "updated"}
+		user, err := lookupUser(client, update.ref)
+		if err != nil {
+			fail(i, err)
+			continue
+		}
+		results[i].ID = user.ID
+		if _, ok := specs[user.ID]; ok {
+			fail(i, invalidInput("user '%s' is listed more than once", update.ref))
+			continue
+		}
+		specs[user.ID] = update.spec
+		ids[i] = user.ID
+	}
+	for i, id := range ids {
+		if id == "" {
+			continue
+		}
+		spec, ok := specs[id]
+		if !ok {
+			fail(i, notFound("no update found for user %s", id))
+			continue
+		}
+		profile, _, err := client.Users.LegacyGetUserByUUID(id)
+		if err != nil {
+			fail(i, err)
+			continue
+		}
+		profile.ID = id
+		spec.applyTo(profile)
+		if _, _, err := client.Users.LegacyUpdateUser(*profile); err != nil {
+			fail(i, err)
+		}
+	}
+	return results, firstErr
+}
+
+func init() {
+	iamUsersCmd.AddCommand(iamUsersUpdateCmd)
+	iamUsersUpdateCmd.Flags().String("email", "", "Email address")
+	iamUsersUpdateCmd.Flags().String("given-name", "", "Given name")
+	iamUsersUpdateCmd.Flags().String("family-name", "", "Family name")
+	iamUsersUpdateCmd.Flags().String("mobile", "", "Mobile phone number")
+	iamUsersUpdateCmd.Flags().String("language", "", "Preferred language, e.g. en-US")
+	iamUsersUpdateCmd.Flags().StringP("file", "f", "", "Update users from a JSON, YAML or CSV file")
+}
@@ -0,0 +1,140 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/spf13/cobra"
+)
+
+type whoami struct {
+	Username     string        `json:"username"`
+	Subject      string        `json:"subject"`
+	IdentityType string        `json:"identityType"`
+	ClientID     string        `json:"clientId"`
+	Organization string        `json:"organization"`
+	Expires      time.Time     `json:"expires"`
+	Permissions  []string      `json:"permissions,omitempty"`
+	Grants       []accessGrant `json:"grants,omitempty"`
+	Unexplained  []string      `json:"unexplained,omitempty"`
+}
+
+// iamWhoamiCmd represents the whoami command
+var iamWhoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the logged in identity",
+	Long: `Shows the identity of the current IAM token.
+
+Use --explain to show the group -> role -> permission chains which grant the
+effective permissions in the selected organization or the one given by --org.
+Permissions no chain accounts for are inherited, e.g. from a parent organization.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		org, _ := cmd.Flags().GetString("org")
+		if org == "" {
+			org = currentWorkspace.IAMSelectedOrg
+		}
+		explain, _ := cmd.Flags().GetBool("explain")
+		if explain && org == "" {
+			return errNoOrgSelected
+		}
+		iamClient, err := getIAMClient(cmd)
+		if err != nil {
+			return fmt.Errorf("error initalizing IAM client: %w", err)
+		}
+		var opts []iam.OptionFunc
+		if org != "" {
+			opts = append(opts, iam.WithOrgContext(org))
+		}
+		introspect, _, err := iamClient.Introspect(opts...)
+		if err != nil {
+			return fmt.Errorf("error performing IAM introspect: %w", err)
+		}
+		me := whoami{
+			Username:     introspect.Username,
+			Subject:      introspect.Sub,
+			IdentityType: introspect.IdentityType,
+			ClientID:     introspect.ClientID,
+			Organization: org,
+			Expires:      time.Unix(introspect.Expires, 0),
+		}
+		for _, o := range introspect.Organizations.OrganizationList {
+			if o.OrganizationID == org {
+				permissions := o.EffectivePermissions
+				if len(permissions) == 0 {
+					permissions = o.Permissions
+				}
+				me.Permissions = make([]string, 0, len(permissions))
+				for _, p := range permissions {
+					me.Permissions = append(me.Permissions, strings.ToUpper(p))
+				}
+			}
+		}
+		if explain {
+			memberType, ok := groupMemberTypes[strings.ToLower(me.IdentityType)]
+			if !ok {
+				memberType = iam.GroupMemberTypeUser
+			}
+			if me.Grants, err = newAccessExplainer(iamClient).grants(org, memberType, me.Subject); err != nil {
+				return err
+			}
+			me.Unexplained = unexplainedPermissions(me.Permissions, me.Grants)
+		}
+
+		if !tableOutput() {
+			return printObject(me)
+		}
+		rows := [][]interface{}{
+			{"username", me.Username},
+			{"subject", me.Subject},
+			{"identity type", me.IdentityType},
+			{"client", me.ClientID},
+			{"organization", me.Organization},
+			{"permissions", len(me.Permissions)},
+			{"expires", me.Expires.Format(time.RFC3339)},
+		}
+		if err := printOutput(me, []string{"field", "value"}, rows); err != nil {
+			return err
+		}
+		if !explain {
+			return nil
+		}
+		fmt.Println()
+		if err := printGrants(me.Grants); err != nil {
+			return err
+		}
+		if len(me.Unexplained) > 0 {
+			fmt.Fprintf(os.Stderr, "\ninherited or granted outside this organization's groups: %s\n", strings.Join(me.Unexplained, ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	iamCmd.AddCommand(iamWhoamiCmd)
+	iamWhoamiCmd.Flags().Bool("explain", false, "Show which groups and roles grant each permission")
+	iamWhoamiCmd.Flags().String("org", "", "Organization ID (default is the selected organization)")
+}
@@ -37,8 +37,8 @@ var ironCmd = &cobra.Command{
 	Use:   "iron",
 	Short: "Interaction with HSDP IronIO",
 	Long:  `This is a replacement of the iron CLI with a focus on dockerized tasks.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -22,10 +22,8 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
-	"github.com/cheynewallace/tabby"
 	"github.com/dip-software/go-dip-api/iron"
 
 	"github.com/spf13/cobra"
@@ -37,43 +35,39 @@ var clustersCmd = &cobra.Command{
 	Aliases: []string{"cl"},
 	Short:   "List available clusters",
 	Long:    `Lists the available Iron clusters.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		config, err := readIronConfig()
 		if err != nil {
-			fmt.Printf("error reading iron config: %v\n", err)
-			return
+			return fmt.Errorf("error reading iron config: %w", err)
 		}
 		client, err := iron.NewClient(config)
 		if err != nil {
-			fmt.Printf("error configuring iron client: %v\n", err)
-			return
+			return fmt.Errorf("error configuring iron client: %w", err)
 		}
-		if !jsonOut {
+		if tableOutput() {
 			fmt.Printf("retrieving clusters...\n\n")
 		}
 		clusters, _, err := client.Clusters.GetClusters()
 		if err != nil {
-			fmt.Printf("error retrieving clusters: %v\n", err)
-			return
+			return fmt.Errorf("error retrieving clusters: %w", err)
 		}
 		cl, _, _ := client.Clusters.GetCluster(config.ClusterInfo[0].ClusterID)
 		if cl != nil {
 			*clusters = append(*clusters, *cl)
 		}
-		if jsonOut {
-			data, _ := json.Marshal(clusters)
-			fmt.Printf("%s\n", string(data))
-			return
-		}
-		t := tabby.New()
-		t.AddHeader("cluster id", "name", "available", "total", "cpu", "memory", "disk")
+		rows := make([][]interface{}, 0)
 		if clusters != nil {
 			for _, cl := range *clusters {
-				t.AddLine(cl.ID, cl.Name, cl.RunnersAvailable, cl.RunnersTotal, cl.CPUShare, cl.Memory, cl.DiskSpace)
+				rows = append(rows, []interface{}{cl.ID, cl.Name, cl.RunnersAvailable, cl.RunnersTotal, cl.CPUShare, cl.Memory, cl.DiskSpace})
 			}
 		}
-		t.Print()
-		fmt.Printf("\n")
+		if err := printOutput(clusters, []string{"cluster id", "name", "available", "total", "cpu", "memory", "disk"}, rows); err != nil {
+			return err
+		}
+		if tableOutput() {
+			fmt.Printf("\n")
+		}
+		return nil
 	},
 }
 
@@ -31,8 +31,8 @@ var codesCmd = &cobra.Command{
 	Aliases: []string{"c", "co"},
 	Short:   "Manage registration of codes with Iron",
 	Long:    `Manage registration of codes with Iron`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
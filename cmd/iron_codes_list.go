@@ -22,11 +22,9 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"time"
 
-	"github.com/cheynewallace/tabby"
 	"github.com/dip-software/go-dip-api/iron"
 
 	"github.com/spf13/cobra"
@@ -43,40 +41,35 @@ and usage of using your command. For example:
 Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		config, err := readIronConfig()
 		if err != nil {
-			fmt.Printf("error reading iron config: %v\n", err)
-			return
+			return fmt.Errorf("error reading iron config: %w", err)
 		}
 		client, err := iron.NewClient(config)
 		if err != nil {
-			fmt.Printf("error configuring iron client: %v\n", err)
-			return
+			return fmt.Errorf("error configuring iron client: %w", err)
 		}
-		if !jsonOut {
+		if tableOutput() {
 			fmt.Printf("retrieving codes...\n\n")
 		}
 		codes, _, err := client.Codes.GetCodes()
 		if err != nil {
-			fmt.Printf("error getting codes: %v\n", err)
-			return
+			return fmt.Errorf("error getting codes: %w", err)
 		}
 		if codes == nil {
-			fmt.Printf("no codes found.\n")
-			return
+			if !tableOutput() {
+				codes = &[]iron.Code{}
+			} else {
+				fmt.Printf("no codes found.\n")
+				return nil
+			}
 		}
-		if jsonOut {
-			data, _ := json.Marshal(codes)
-			fmt.Printf("%s\n", data)
-			return
-		}
-		t := tabby.New()
-		t.AddHeader("code name", "revisions", "last modified")
+		rows := make([][]interface{}, 0)
 		for _, code := range *codes {
-			t.AddLine(code.Name, code.Rev, code.LatestChange.Format(time.RFC3339))
+			rows = append(rows, []interface{}{code.Name, code.Rev, code.LatestChange.Format(time.RFC3339)})
 		}
-		t.Print()
+		return printOutput(codes, []string{"code name", "revisions", "last modified"}, rows)
 	},
 }
 
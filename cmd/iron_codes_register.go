@@ -34,24 +34,21 @@ var ironRegisterCmd = &cobra.Command{
 	Aliases: []string{"r"},
 	Short:   "Register a docker image as an Iron code",
 	Long:    `Registers a docker image as an Iron code`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			_ = cmd.Help()
-			return
+			return cmd.Help()
 		}
 		config, err := readIronConfig()
 		if err != nil {
-			fmt.Printf("error reading iron config: %v\n", err)
-			return
+			return fmt.Errorf("error reading iron config: %w", err)
 		}
 		if len(config.ClusterInfo) == 0 {
-			fmt.Printf("missing required cluster info in config")
+			return invalidInput("missing required cluster info in iron config")
 		}
 		config.Debug = debug
 		client, err := iron.NewClient(config)
 		if err != nil {
-			fmt.Printf("error configuring iron client: %v\n", err)
-			return
+			return fmt.Errorf("error configuring iron client: %w", err)
 		}
 		name := strings.Split(args[0], ":")[0]
 		code, resp, err := client.Codes.CreateOrUpdateCode(iron.Code{
@@ -59,15 +56,13 @@ var ironRegisterCmd = &cobra.Command{
 			Image: args[0],
 		})
 		if err != nil {
-			fmt.Printf("error registering code: %v\n", err)
-			return
+			return fmt.Errorf("error registering code: %w", err)
 		}
-		if code != nil && code.Name != "" {
-			fmt.Printf("registered %s, revision %d\n", code.Name, code.Rev)
-		} else {
-			fmt.Printf("unexpected error registering code: %d\n", resp.StatusCode)
+		if code == nil || code.Name == "" {
+			return statusError(resp.StatusCode, "unexpected error registering code: %d", resp.StatusCode)
 		}
-		fmt.Printf("\n")
+		fmt.Printf("registered %s, revision %d\n\n", code.Name, code.Rev)
+		return nil
 	},
 }
 
@@ -30,8 +30,8 @@ var dockerCmd = &cobra.Command{
 	Use:   "docker login",
 	Short: "Regiser docker credentials with Iron",
 	Long:  `Register docker credentials with Iron`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
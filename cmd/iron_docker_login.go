@@ -33,20 +33,18 @@ var dockerLoginCmd = &cobra.Command{
 	Use:   "login -u username -p password -e email -s server",
 	Short: "register docker credentials with Iron",
 	Long:  `register docker credentials with Iron`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		config, err := readIronConfig()
 		if err != nil {
-			fmt.Printf("error reading iron config: %v\n", err)
-			return
+			return fmt.Errorf("error reading iron config: %w", err)
 		}
 		if len(config.ClusterInfo) == 0 {
-			fmt.Printf("missing required cluster info in config")
+			return invalidInput("missing required cluster info in iron config")
 		}
 		config.Debug = debug
 		client, err := iron.NewClient(config)
 		if err != nil {
-			fmt.Printf("error configuring iron client: %v\n", err)
-			return
+			return fmt.Errorf("error configuring iron client: %w", err)
 		}
 
 		username, _ := cmd.Flags().GetString("username")
@@ -61,16 +59,13 @@ var dockerLoginCmd = &cobra.Command{
 			ServerAddress: server,
 		})
 		if err != nil {
-			fmt.Printf("error registering credentials: %v\n", err)
-			fmt.Printf("\n")
-			_ = cmd.Help()
-			return
+			return fmt.Errorf("error registering credentials: %w", err)
 		}
 		if !ok {
-			fmt.Printf("credentials verification failed.\n")
-			return
+			return authRequired("credentials verification failed")
 		}
 		fmt.Printf("credentials stored successfully.\n")
+		return nil
 	},
 }
 
@@ -23,7 +23,6 @@ THE SOFTWARE.
 */
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -38,40 +37,34 @@ var ironQueueCmd = &cobra.Command{
 	Aliases: []string{"q"},
 	Short:   "Queues tasks on a cluster",
 	Long:    `Queues tasks on a cluster`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			_ = cmd.Help()
-			return
+			return cmd.Help()
 		}
 		codeName := args[0]
 		if codeName == "" {
-			fmt.Printf("must specify code name as argument\n")
-			return
+			return invalidInput("must specify code name as argument")
 		}
 		payloadFile, _ := cmd.Flags().GetString("payload")
 		payloadData, err := os.ReadFile(payloadFile)
 		if err != nil {
-			fmt.Printf("error reading payload data: %v\n", err)
-			return
+			return invalidInput("error reading payload data: %w", err)
 		}
 
 		cluster, _ := cmd.Flags().GetString("cluster")
 
 		config, err := readIronConfig()
 		if err != nil {
-			fmt.Printf("error reading iron config: %v\n", err)
-			return
+			return fmt.Errorf("error reading iron config: %w", err)
 		}
 		client, err := iron.NewClient(config)
 		if err != nil {
-			fmt.Printf("error configuring iron client: %v\n", err)
-			return
+			return fmt.Errorf("error configuring iron client: %w", err)
 		}
 		defer client.Close()
 		if cluster == "" {
 			if len(config.ClusterInfo) == 0 {
-				fmt.Printf("no default cluster, must specify cluster ID explicitly for this command\n")
-				return
+				return invalidInput("no default cluster, must specify cluster ID explicitly for this command")
 			}
 			cluster = config.ClusterInfo[0].ClusterID
 			fmt.Printf("using first cluster in list: %s\n", cluster)
@@ -80,13 +73,11 @@ var ironQueueCmd = &cobra.Command{
 		if cluster != "" { // Encryption needed
 			key := ""
 			if key = config.ClusterInfo[0].Pubkey; key == "" {
-				fmt.Println("missing public key in configuration")
-				return
+				return invalidInput("missing public key in configuration")
 			}
 			ciphertext, err := iron.EncryptPayload([]byte(key), payloadData)
 			if err != nil {
-				fmt.Printf("error encrypting payload: %v\n", err)
-				return
+				return fmt.Errorf("error encrypting payload: %w", err)
 			}
 			taskData = ciphertext
 		}
@@ -98,11 +89,9 @@ var ironQueueCmd = &cobra.Command{
 		}
 		scheduledTask, _, err := client.Tasks.QueueTask(task)
 		if err != nil {
-			fmt.Printf("error queueing task: %v\n", err)
-			return
+			return fmt.Errorf("error queueing task: %w", err)
 		}
-		data, _ := json.Marshal(scheduledTask)
-		fmt.Printf("%s\n", pretty(data))
+		return printObject(scheduledTask)
 	},
 }
 
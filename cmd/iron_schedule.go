@@ -35,35 +35,30 @@ var ironScheduleCmd = &cobra.Command{
 	Aliases: []string{"s"},
 	Short:   "Schedule a task on a cluster",
 	Long:    `Schedule a task on a cluster`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			_ = cmd.Help()
-			return
+			return cmd.Help()
 		}
 		code := args[0]
 		config, err := readIronConfig()
 		if err != nil {
-			fmt.Printf("error reading iron config: %v\n", err)
-			return
+			return fmt.Errorf("error reading iron config: %w", err)
 		}
 		if len(config.ClusterInfo) == 0 {
-			fmt.Printf("missing required cluster info in config")
+			return invalidInput("missing required cluster info in iron config")
 		}
 		config.Debug = debug
 		client, err := iron.NewClient(config)
 		if err != nil {
-			fmt.Printf("error configuring iron client: %v\n", err)
-			return
+			return fmt.Errorf("error configuring iron client: %w", err)
 		}
 		payload, _ := cmd.Flags().GetString("payload")
 		if payload == "" {
-			fmt.Printf("payload is required\n")
-			return
+			return invalidInput("payload is required")
 		}
 		encryptedPayload, err := config.ClusterInfo[0].Encrypt([]byte(payload))
 		if err != nil {
-			fmt.Printf("error encrypting payload: %v\n", err)
-			return
+			return fmt.Errorf("error encrypting payload: %w", err)
 		}
 		timeout, _ := cmd.Flags().GetInt("timeout")
 		runEvery, _ := cmd.Flags().GetInt("every")
@@ -81,14 +76,13 @@ var ironScheduleCmd = &cobra.Command{
 			Cluster:  cluster,
 		})
 		if err != nil {
-			fmt.Printf("error scheduling task: %v\n", err)
-			return
+			return fmt.Errorf("error scheduling task: %w", err)
 		}
 		if schedule != nil {
 			fmt.Printf("scheduled as: %s\n", schedule.ID)
-			return
+			return nil
 		}
-		fmt.Printf("error status: %d\n", resp.StatusCode)
+		return statusError(resp.StatusCode, "error status: %d", resp.StatusCode)
 	},
 }
 
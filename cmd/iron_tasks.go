@@ -31,8 +31,8 @@ var ironTasksCmd = &cobra.Command{
 	Aliases: []string{"t"},
 	Short:   "Manage tasks on Iron",
 	Long:    `Manage tasks on Iron`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
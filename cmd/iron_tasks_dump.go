@@ -22,7 +22,6 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/dip-software/go-dip-api/iron"
@@ -35,28 +34,24 @@ var dumpCmd = &cobra.Command{
 	Aliases: []string{"d"},
 	Short:   "Dump a scheduled task",
 	Long:    `Dumps all known information about a scheduled task.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			_ = cmd.Help()
-			return
+			return cmd.Help()
 		}
 		config, err := readIronConfig()
 		if err != nil {
-			fmt.Printf("error reading iron config: %v\n", err)
-			return
+			return fmt.Errorf("error reading iron config: %w", err)
 		}
 		client, err := iron.NewClient(config)
 		if err != nil {
-			fmt.Printf("error configuring iron client: %v\n", err)
-			return
+			return fmt.Errorf("error configuring iron client: %w", err)
 		}
-		if !jsonOut {
+		if tableOutput() {
 			fmt.Printf("retrieving scheduled task details...\n\n")
 		}
 		schedules, _, err := client.Schedules.GetSchedules()
 		if err != nil {
-			fmt.Printf("error retrieving schedules: %v\n", err)
-			return
+			return fmt.Errorf("error retrieving schedules: %w", err)
 		}
 		scheduleID := ""
 		for _, s := range *schedules {
@@ -66,19 +61,13 @@ var dumpCmd = &cobra.Command{
 			}
 		}
 		if scheduleID == "" {
-			fmt.Printf("schedule not found: %s\n", args[0])
+			return notFound("schedule not found: %s", args[0])
 		}
 		schedule, _, err := client.Schedules.GetSchedule(scheduleID)
 		if err != nil {
-			fmt.Printf("error retrieving schedule: %v\n", err)
-			return
+			return fmt.Errorf("error retrieving schedule: %w", err)
 		}
-		data, err := json.Marshal(schedule)
-		if err != nil {
-			fmt.Printf("error marshalling schedule result: %v\n", err)
-			return
-		}
-		fmt.Println(pretty(data))
+		return printObject(schedule)
 	},
 }
 
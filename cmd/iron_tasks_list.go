@@ -22,7 +22,6 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -37,37 +36,36 @@ var ironTasksListCmd = &cobra.Command{
 	Aliases: []string{"l", "ls"},
 	Short:   "List tasks on Iron",
 	Long:    `Lists task on Iron`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		config, err := readIronConfig()
 		if err != nil {
-			fmt.Printf("error reading iron config: %v\n", err)
-			return
+			return fmt.Errorf("error reading iron config: %w", err)
 		}
 		client, err := iron.NewClient(config)
 		if err != nil {
-			fmt.Printf("error configuring iron client: %v\n", err)
-			return
+			return fmt.Errorf("error configuring iron client: %w", err)
 		}
-		if !jsonOut {
+		if tableOutput() {
 			fmt.Printf("retrieving tasks and schedules...\n\n")
 		}
 		tasks, _, err := client.Tasks.GetTasks()
 		if err != nil {
-			fmt.Printf("error getting tasks: %v\n", err)
-			return
+			return fmt.Errorf("error getting tasks: %w", err)
 		}
 		if tasks == nil {
-			if jsonOut {
-				fmt.Printf("[]\n")
-				return
+			if !tableOutput() {
+				tasks = &[]iron.Task{}
+			} else {
+				fmt.Printf("no tasks found.\n")
+				return nil
 			}
-			fmt.Printf("no tasks found.\n")
-			return
 		}
-		if jsonOut {
-			data, _ := json.Marshal(tasks)
-			fmt.Printf("%s\n", data)
-			return
+		if !tableOutput() {
+			rows := make([][]interface{}, 0)
+			for _, task := range *tasks {
+				rows = append(rows, []interface{}{task.ID, task.CodeName, task.Status})
+			}
+			return printOutput(tasks, []string{"task id", "code name", "status"}, rows)
 		}
 		t := tabby.New()
 		type taskStats struct {
@@ -112,8 +110,7 @@ var ironTasksListCmd = &cobra.Command{
 		fmt.Printf("\n")
 		schedules, _, err := client.Schedules.GetSchedules()
 		if err != nil {
-			fmt.Printf("error retrieving schedules: %v\n", err)
-			return
+			return fmt.Errorf("error retrieving schedules: %w", err)
 		}
 		t = tabby.New()
 		t.AddHeader("schedule", "every", "status", "last", "next", "runs")
@@ -130,6 +127,7 @@ var ironTasksListCmd = &cobra.Command{
 			fmt.Printf("no scheduled tasks found\n")
 		}
 		fmt.Printf("\n")
+		return nil
 	},
 }
 
@@ -0,0 +1,291 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/cheynewallace/tabby"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormat is set by the global --output/-o flag
+var outputFormat string
+
+const outputFormats = "table, json, yaml, csv, jsonpath=<expr>, go-template=<template>, go-template-file=<file>"
+
+// outputKind splits --output into its kind and optional argument. The legacy
+// --json flag maps to json when no explicit format was given.
+func outputKind() (string, string) {
+	if outputFormat == "" {
+		if jsonOut {
+			return "json", ""
+		}
+		return "table", ""
+	}
+	kind, arg, _ := strings.Cut(outputFormat, "=")
+	return kind, arg
+}
+
+// tableOutput returns true when output is meant for humans, so progress
+// messages and headings can be printed without breaking machine output
+func tableOutput() bool {
+	kind, _ := outputKind()
+	return kind == "table"
+}
+
+// printOutput renders a list result in the selected output format. The
+// headers and rows are used for table and csv output, data for all others.
+func printOutput(data interface{}, headers []string, rows [][]interface{}) error {
+	kind, _ := outputKind()
+	switch kind {
+	case "table":
+		t := tabby.New()
+		header := make([]interface{}, len(headers))
+		for i, h := range headers {
+			header[i] = h
+		}
+		t.AddHeader(header...)
+		for _, row := range rows {
+			t.AddLine(row...)
+		}
+		t.Print()
+		return nil
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		if err := w.Write(headers); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			record := make([]string, len(row))
+			for i, v := range row {
+				record[i] = fmt.Sprintf("%v", v)
+			}
+			if err := w.Write(record); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		return w.Error()
+	}
+	return writeStructured(os.Stdout, data)
+}
+
+// printObject renders a single result. Table output falls back to indented JSON.
+func printObject(data interface{}) error {
+	kind, _ := outputKind()
+	switch kind {
+	case "table":
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		fmt.Println(pretty(raw))
+		return nil
+	case "csv":
+		return fmt.Errorf("csv output is only supported for lists")
+	}
+	return writeStructured(os.Stdout, data)
+}
+
+// writeStructured handles the formats which operate on the JSON form of data
+func writeStructured(w io.Writer, data interface{}) error {
+	kind, arg := outputKind()
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if kind == "json" {
+		if outputFormat == "" { // --json keeps its compact output
+			_, err = fmt.Fprintf(w, "%s\n", raw)
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", pretty(raw))
+		return err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return err
+	}
+	switch kind {
+	case "yaml":
+		out, err := yaml.Marshal(generic)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	case "jsonpath":
+		out, err := evalJSONPath(arg, generic)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", out)
+		return err
+	case "go-template", "go-template-file":
+		text := arg
+		if kind == "go-template-file" {
+			content, err := os.ReadFile(arg)
+			if err != nil {
+				return err
+			}
+			text = string(content)
+		}
+		tmpl, err := template.New("output").Funcs(template.FuncMap{
+			"json": func(v interface{}) (string, error) {
+				out, err := json.Marshal(v)
+				return string(out), err
+			},
+		}).Parse(text)
+		if err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+		return tmpl.Execute(w, generic)
+	}
+	return fmt.Errorf("unknown output format '%s', valid formats: %s", kind, outputFormats)
+}
+
+// evalJSONPath evaluates a kubectl style JSONPath subset against data.
+// Supported are {} wrapped expressions mixed with literal text, field
+// access (.name), indexes ([0], [-1]) and wildcards ([*]).
+func evalJSONPath(expr string, data interface{}) (string, error) {
+	if !strings.Contains(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+	var out bytes.Buffer
+	for len(expr) > 0 {
+		start := strings.Index(expr, "{")
+		if start < 0 {
+			out.WriteString(expr)
+			break
+		}
+		out.WriteString(expr[:start])
+		end := strings.Index(expr[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("unclosed '{' in jsonpath")
+		}
+		results, err := jsonPathSelect(expr[start+1:start+end], data)
+		if err != nil {
+			return "", err
+		}
+		values := make([]string, 0, len(results))
+		for _, r := range results {
+			switch v := r.(type) {
+			case string:
+				values = append(values, v)
+			case map[string]interface{}, []interface{}:
+				raw, _ := json.Marshal(v)
+				values = append(values, string(raw))
+			case nil:
+				values = append(values, "")
+			default:
+				values = append(values, fmt.Sprintf("%v", v))
+			}
+		}
+		out.WriteString(strings.Join(values, " "))
+		expr = expr[start+end+1:]
+	}
+	return out.String(), nil
+}
+
+func jsonPathSelect(path string, data interface{}) ([]interface{}, error) {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "$")
+	current := []interface{}{data}
+	for len(path) > 0 {
+		next := make([]interface{}, 0)
+		switch path[0] {
+		case '.':
+			path = path[1:]
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			name := path[:end]
+			path = path[end:]
+			if name == "" {
+				continue
+			}
+			for _, c := range current {
+				if m, ok := c.(map[string]interface{}); ok {
+					if v, found := m[name]; found {
+						next = append(next, v)
+					}
+				}
+			}
+		case '[':
+			end := strings.Index(path, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '[' in jsonpath")
+			}
+			index := path[1:end]
+			path = path[end+1:]
+			for _, c := range current {
+				switch v := c.(type) {
+				case []interface{}:
+					if index == "*" {
+						next = append(next, v...)
+						continue
+					}
+					i, err := strconv.Atoi(index)
+					if err != nil {
+						return nil, fmt.Errorf("invalid index '%s' in jsonpath", index)
+					}
+					if i < 0 {
+						i += len(v)
+					}
+					if i >= 0 && i < len(v) {
+						next = append(next, v[i])
+					}
+				case map[string]interface{}:
+					if index == "*" {
+						keys := make([]string, 0, len(v))
+						for key := range v {
+							keys = append(keys, key)
+						}
+						sort.Strings(keys)
+						for _, key := range keys {
+							next = append(next, v[key])
+						}
+						continue
+					}
+					if item, found := v[strings.Trim(index, "'\"")]; found {
+						next = append(next, item)
+					}
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unexpected '%c' in jsonpath", path[0])
+		}
+		current = next
+	}
+	return current, nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestEvalJSONPath(t *testing.T) {
+	var data interface{}
+	_ = json.Unmarshal([]byte(`{"items":[{"name":"a","id":1},{"name":"b","id":2}],"meta":{"total":2}}`), &data)
+
+	tests := map[string]string{
+		"{.items[*].name}":    "a b",
+		".items[0].id":        "1",
+		"{.items[-1].name}":   "b",
+		"total={.meta.total}": "total=2",
+		"{.items[1]}":         `{"id":2,"name":"b"}`,
+		"{.missing}":          "",
+	}
+	for expr, want := range tests {
+		got, err := evalJSONPath(expr, data)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", expr, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: got '%s', want '%s'", expr, got, want)
+		}
+	}
+	if _, err := evalJSONPath("{.items[x]}", data); err == nil {
+		t.Error("expected error for invalid index")
+	}
+}
+
+func TestWriteStructuredFormats(t *testing.T) {
+	defer func(format string) { outputFormat = format }(outputFormat)
+	data := []map[string]string{{"name": "a"}}
+
+	tests := map[string]string{
+		"yaml":                 "- name: a\n",
+		"jsonpath={.[0].name}": "a\n",
+		"go-template={{range .}}{{.name}}{{end}}": "a",
+	}
+	for format, want := range tests {
+		outputFormat = format
+		var buf bytes.Buffer
+		if err := writeStructured(&buf, data); err != nil {
+			t.Errorf("%s: unexpected error: %v", format, err)
+			continue
+		}
+		if buf.String() != want {
+			t.Errorf("%s: got %q, want %q", format, buf.String(), want)
+		}
+	}
+	outputFormat = "xml"
+	if err := writeStructured(&bytes.Buffer{}, data); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -30,8 +30,8 @@ var pkiCmd = &cobra.Command{
 	Use:   "pki",
 	Short: "PKI related commands",
 	Long:  `Supports the HSDP PKI service.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -31,8 +31,8 @@ var pkiCertsCmd = &cobra.Command{
 	Aliases: []string{"c"},
 	Short:   "PKI certificate related commands",
 	Long:    `List and manage HSDP PKI certificates`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
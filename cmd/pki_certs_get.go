@@ -16,12 +16,10 @@ limitations under the License.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/dip-software/go-dip-api/console"
 	"github.com/dip-software/go-dip-api/pki"
 	"net/http"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -32,10 +30,9 @@ var pkiCertsGetCmd = &cobra.Command{
 	Aliases: []string{"g"},
 	Short:   "Get information about a certifcate",
 	Long:    `Retrieves and lists certificate info based on serial`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 2 {
-			fmt.Printf("must specify PKI logical path and certicate serial\n")
-			os.Exit(1)
+			return invalidInput("must specify PKI logical path and certicate serial")
 		}
 		logicalPath := args[0]
 		serial := args[1]
@@ -48,43 +45,33 @@ var pkiCertsGetCmd = &cobra.Command{
 			environment = currentWorkspace.DefaultEnvironment
 		}
 		if currentWorkspace.UAAToken == "" {
-			fmt.Printf("Login to UAA first using 'uaa login'\n")
-			os.Exit(1)
+			return errUAALoginRequired
 		}
 		consoleClient, err := console.NewClient(http.DefaultClient, &console.Config{
 			Region: region,
 		})
 		if err != nil {
-			fmt.Printf("error initializing CONSOLE client: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error initializing CONSOLE client: %w", err)
 		}
 		consoleClient.SetTokens(currentWorkspace.UAAToken,
 			currentWorkspace.UAARefreshToken, currentWorkspace.UAAIDToken, currentWorkspace.UAAAccessTokenExpires)
 		iamClient, err := getIAMClient(cmd)
 		if err != nil {
-			fmt.Printf("error initializing IAM client: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error initializing IAM client: %w", err)
 		}
 		pkiClient, err := pki.NewClient(consoleClient, iamClient, &pki.Config{
 			Region:      region,
 			Environment: environment,
 		})
 		if err != nil {
-			fmt.Printf("error initializing PKI client: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error initializing PKI client: %w", err)
 		}
 		cert, _, err := pkiClient.Services.GetCertificateBySerial(logicalPath, serial, nil)
 		if err != nil {
-			fmt.Printf("error getting certificate: %v\n", err)
-			os.Exit(1)
-		}
-		data, err := json.Marshal(cert)
-		if err != nil {
-			fmt.Printf("error marshalling certificate result: %v\n", err)
-			return
+			return fmt.Errorf("error getting certificate: %w", err)
 		}
 		persistUAACredentials(consoleClient)
-		fmt.Println(pretty(data))
+		return printObject(cert)
 	},
 }
 
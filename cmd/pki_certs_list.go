@@ -22,12 +22,10 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/dip-software/go-dip-api/console"
 	"github.com/dip-software/go-dip-api/pki"
 	"net/http"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -38,10 +36,9 @@ var pkiCertsListCmd = &cobra.Command{
 	Aliases: []string{"l", "li"},
 	Short:   "Lists known certificates",
 	Long:    `Lists the known certificates under a HSDP PKI policy CA`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			fmt.Printf("must specify PKI logical path\n")
-			os.Exit(1)
+			return invalidInput("must specify PKI logical path")
 		}
 		logicalPath := args[0]
 		region, _ := cmd.Flags().GetString("region")
@@ -53,43 +50,37 @@ var pkiCertsListCmd = &cobra.Command{
 			environment = currentWorkspace.DefaultEnvironment
 		}
 		if currentWorkspace.UAAToken == "" {
-			fmt.Printf("Login to UAA first using 'uaa login'\n")
-			os.Exit(1)
+			return errUAALoginRequired
 		}
 		consoleClient, err := console.NewClient(http.DefaultClient, &console.Config{
 			Region: region,
 		})
 		if err != nil {
-			fmt.Printf("error initializing CONSOLE client: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error initializing CONSOLE client: %w", err)
 		}
 		consoleClient.SetTokens(currentWorkspace.UAAToken,
 			currentWorkspace.UAARefreshToken, currentWorkspace.UAAIDToken, currentWorkspace.UAAAccessTokenExpires)
 		iamClient, err := getIAMClient(cmd)
 		if err != nil {
-			fmt.Printf("error initializing IAM client: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error initializing IAM client: %w", err)
 		}
 		pkiClient, err := pki.NewClient(consoleClient, iamClient, &pki.Config{
 			Region:      region,
 			Environment: environment,
 		})
 		if err != nil {
-			fmt.Printf("error initializing PKI client: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error initializing PKI client: %w", err)
 		}
 		certs, _, err := pkiClient.Services.GetCertificates(logicalPath, nil)
 		if err != nil {
-			fmt.Printf("error getting certificate list: %v\n", err)
-			os.Exit(1)
-		}
-		data, err := json.Marshal(certs)
-		if err != nil {
-			fmt.Printf("error marshalling CerticateList result: %v\n", err)
-			return
+			return fmt.Errorf("error getting certificate list: %w", err)
 		}
 		persistUAACredentials(consoleClient)
-		fmt.Println(pretty(data))
+		rows := make([][]interface{}, 0)
+		for _, serial := range certs.Data.Keys {
+			rows = append(rows, []interface{}{serial})
+		}
+		return printOutput(certs, []string{"serial"}, rows)
 	},
 }
 
@@ -22,12 +22,10 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/dip-software/go-dip-api/console"
 	"github.com/dip-software/go-dip-api/pki"
 	"net/http"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -38,10 +36,9 @@ var pkiListCmd = &cobra.Command{
 	Aliases: []string{"l"},
 	Short:   "List PKI onboardings",
 	Long:    `Shows PKI onboarind information`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			fmt.Printf("must specify PKI logical path\n")
-			os.Exit(1)
+			return invalidInput("must specify PKI logical path")
 		}
 		logicalPath := args[0]
 		region, _ := cmd.Flags().GetString("region")
@@ -53,43 +50,33 @@ var pkiListCmd = &cobra.Command{
 			environment = currentWorkspace.DefaultEnvironment
 		}
 		if currentWorkspace.UAAToken == "" {
-			fmt.Printf("Login to UAA first using 'uaa login'\n")
-			os.Exit(1)
+			return errUAALoginRequired
 		}
 		consoleClient, err := console.NewClient(http.DefaultClient, &console.Config{
 			Region: region,
 		})
 		if err != nil {
-			fmt.Printf("error initializing CONSOLE client: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error initializing CONSOLE client: %w", err)
 		}
 		consoleClient.SetTokens(currentWorkspace.UAAToken,
 			currentWorkspace.UAARefreshToken, currentWorkspace.UAAIDToken, currentWorkspace.UAAAccessTokenExpires)
 		iamClient, err := getIAMClient(cmd)
 		if err != nil {
-			fmt.Printf("error initializing IAM client: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error initializing IAM client: %w", err)
 		}
 		pkiClient, err := pki.NewClient(consoleClient, iamClient, &pki.Config{
 			Region:      region,
 			Environment: environment,
 		})
 		if err != nil {
-			fmt.Printf("error initializing PKI client: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error initializing PKI client: %w", err)
 		}
 		tenant, _, err := pkiClient.Tenants.Retrieve(logicalPath)
 		if err != nil {
-			fmt.Printf("error retrieving PKI from logical path '%s': %v\n", logicalPath, err)
-			os.Exit(1)
-		}
-		data, err := json.Marshal(tenant)
-		if err != nil {
-			fmt.Printf("error marshalling Tenant result: %v\n", err)
-			return
+			return fmt.Errorf("error retrieving PKI from logical path '%s': %w", logicalPath, err)
 		}
 		persistUAACredentials(consoleClient)
-		fmt.Println(pretty(data))
+		return printObject(tenant)
 	},
 }
 
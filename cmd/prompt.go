@@ -0,0 +1,103 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultPromptFormat = `{{.Workspace}}{{if .Org}}:{{.Org}}{{end}}{{if .Expires}}({{.Expires}}){{end}}`
+
+// promptInfo holds the fields available to prompt format templates
+type promptInfo struct {
+	Workspace   string
+	Org         string
+	OrgID       string
+	Region      string
+	Environment string
+	// Expires is the remaining IAM token lifetime, "expired" or empty when never logged in
+	Expires  string
+	LoggedIn bool
+}
+
+// promptCmd represents the prompt command
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print a compact workspace status for shell prompts",
+	Long: `Prints a compact string like prod-eu:MyOrg(12m) describing the active
+workspace, selected organization and remaining IAM token lifetime.
+
+No network calls are made so it is safe to run on every prompt. The output
+can be customized with a Go template using --format or HS_PROMPT_FORMAT.
+Available fields: .Workspace .Org .OrgID .Region .Environment .Expires .LoggedIn
+
+Use 'hs prompt init <shell>' to get a ready-made snippet for your shell.`,
+	Annotations: map[string]string{annotationNoSecrets: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		if format == "" {
+			format = os.Getenv("HS_PROMPT_FORMAT")
+		}
+		if format == "" {
+			format = defaultPromptFormat
+		}
+		tmpl, err := template.New("prompt").Parse(format)
+		if err != nil {
+			return invalidInput("invalid prompt format: %w", err)
+		}
+		info := promptInfo{
+			Workspace:   currentWorkspace.Name,
+			Org:         currentWorkspace.IAMSelectedOrgName,
+			OrgID:       currentWorkspace.IAMSelectedOrg,
+			Region:      currentWorkspace.IAMRegion,
+			Environment: currentWorkspace.IAMEnvironment,
+		}
+		if info.Region == "" {
+			info.Region = currentWorkspace.DefaultRegion
+		}
+		if info.Environment == "" {
+			info.Environment = currentWorkspace.DefaultEnvironment
+		}
+		if expires := currentWorkspace.iamExpireTime(); expires != nil {
+			if remaining := time.Until(*expires); remaining > 0 {
+				info.Expires = shortDuration(remaining)
+				info.LoggedIn = true
+			} else {
+				info.Expires = "expired"
+			}
+		}
+		if err := tmpl.Execute(os.Stdout, info); err != nil {
+			return fmt.Errorf("error rendering prompt: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+	promptCmd.Flags().StringP("format", "f", "", "Go template for the prompt")
+}
@@ -0,0 +1,90 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var promptSnippets = map[string]string{
+	"bash": `# Add to ~/.bashrc: eval "$(hs prompt init bash)"
+__hs_prompt() {
+  local p
+  p="$(hs prompt 2>/dev/null)"
+  [ -n "$p" ] && printf '[%s] ' "$p"
+}
+PS1='$(__hs_prompt)'"$PS1"
+`,
+	"zsh": `# Add to ~/.zshrc: eval "$(hs prompt init zsh)"
+setopt PROMPT_SUBST
+__hs_prompt() {
+  local p
+  p="$(hs prompt 2>/dev/null)"
+  [[ -n "$p" ]] && print -n "[$p] "
+}
+PROMPT='$(__hs_prompt)'"$PROMPT"
+`,
+	"fish": `# Add to ~/.config/fish/config.fish: hs prompt init fish | source
+function __hs_prompt
+    set -l p (hs prompt 2>/dev/null)
+    test -n "$p"; and printf '[%s] ' $p
+end
+functions -q __hs_original_fish_prompt; or functions -c fish_prompt __hs_original_fish_prompt
+function fish_prompt
+    __hs_prompt
+    __hs_original_fish_prompt
+end
+`,
+	"starship": `# Add to ~/.config/starship.toml: hs prompt init starship >> ~/.config/starship.toml
+[custom.hs]
+command = "hs prompt"
+when = "command -v hs"
+format = "[$output]($style) "
+style = "bold cyan"
+`,
+}
+
+// promptInitCmd represents the prompt init command
+var promptInitCmd = &cobra.Command{
+	Use:         "init <bash|zsh|fish|starship>",
+	Short:       "Print a shell snippet showing hs prompt",
+	Long:        `Prints a snippet which adds the hs prompt to bash, zsh, fish or starship.`,
+	ValidArgs:   []string{"bash", "zsh", "fish", "starship"},
+	Annotations: map[string]string{annotationNoSecrets: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return cmd.Help()
+		}
+		snippet, ok := promptSnippets[args[0]]
+		if !ok {
+			return invalidInput("unsupported shell '%s', use one of: bash, zsh, fish, starship", args[0])
+		}
+		fmt.Print(snippet)
+		return nil
+	},
+}
+
+func init() {
+	promptCmd.AddCommand(promptInitCmd)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+)
+
+func TestPromptCommandsDoNotUnlockSecrets(t *testing.T) {
+	testWorkspaceRoot(t)
+	keyring.MockInit()
+	saveTestWorkspace(t, &workspaceConfig{Name: "dev", SecretStore: secretStoreKeyring, IAMAccessToken: "access"})
+	defer func() { currentWorkspace = nil }()
+
+	for _, cmd := range []*cobra.Command{promptCmd, promptInitCmd} {
+		currentWorkspace = loadTestWorkspace(t, "dev")
+		rootCmd.PersistentPreRun(cmd, nil)
+		if !currentWorkspace.secretsLocked || currentWorkspace.IAMAccessToken == "access" {
+			t.Errorf("%s: expected secrets to stay locked", cmd.Name())
+		}
+	}
+
+	currentWorkspace = loadTestWorkspace(t, "dev")
+	rootCmd.PersistentPreRun(workspaceSecretsCmd, nil)
+	if currentWorkspace.secretsLocked || currentWorkspace.IAMAccessToken != "access" {
+		t.Error("expected other commands to unlock secrets")
+	}
+}
@@ -36,6 +36,7 @@ var debug bool
 var jsonOut bool
 var clientID string
 var clientSecret string
+var workspaceOverride string
 var currentWorkspace *workspaceConfig
 
 // rootCmd represents the base command when called without any subcommands
@@ -47,17 +48,39 @@ Create workspaces to handle mulitple accounts or switch between tenants easily`,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	//	Run: func(cmd *cobra.Command, args []string) { },
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		commandStarted = true
+		if cmd.Annotations[annotationNoSecrets] == "true" {
+			return
+		}
+		unlockWorkspaceSecrets()
+	},
 }
 
+// annotationNoSecrets marks commands which must not unlock workspace secrets,
+// e.g. because they run on every shell prompt
+const annotationNoSecrets = "hs/no-secrets"
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Errors are classified and mapped to the exit codes in errors.go.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	err := rootCmd.Execute()
+	persistIAMSession()
+	if err != nil {
+		if !commandStarted {
+			// Cobra failed before running the command: bad flags, args or unknown command
+			err = &cliError{kind: kindInvalidInput, err: err}
+		}
+		os.Exit(reportError(err))
 	}
 }
 
+// commandStarted is set once argument and flag validation passed
+var commandStarted bool
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
@@ -68,6 +91,8 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.hs.yaml)")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debugging")
 	rootCmd.PersistentFlags().BoolVar(&jsonOut, "json", false, "enable JSON output")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "output format: "+outputFormats)
+	rootCmd.PersistentFlags().StringVarP(&workspaceOverride, "workspace", "w", "", "workspace to use for this invocation (default is $HS_WORKSPACE or the current workspace)")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -80,11 +105,15 @@ func initConfig() {
 	ensureDefault()
 	var err error
 
-	currentWorkspace, err = loadWorkspaceConfig(currentWorkspaceName())
+	workspace := activeWorkspaceName()
+	// --workspace and HS_WORKSPACE end up in the workspace file path
+	if err := validateWorkspaceName(workspace); err != nil {
+		os.Exit(reportError(err))
+	}
+	currentWorkspace, err = loadWorkspaceConfig(workspace)
 
 	if err != nil {
-		fmt.Printf("failed to load workspace: %s\n", currentWorkspaceName())
-		os.Exit(1)
+		os.Exit(reportError(fmt.Errorf("failed to load workspace %s: %w", workspace, err)))
 	}
 	if cfgFile != "" {
 		// Use config file from the flag.
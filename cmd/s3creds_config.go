@@ -33,19 +33,18 @@ var s3credsConfigCmd = &cobra.Command{
 	Aliases: []string{"c"},
 	Short:   "Configure S3 Credentials setup",
 	Long:    `Configure the S3 Credentials setup.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		productKey, _ := cmd.Flags().GetString("product-key")
 
 		if productKey == "" {
-			_ = cmd.Help()
-			return
+			return cmd.Help()
 		}
 		currentWorkspace.S3CredsProductKey = productKey
-		if err := currentWorkspace.save(); err == nil {
-			fmt.Printf("OK\n")
-		} else {
-			fmt.Printf("failed to store config: %v\n", err)
+		if err := currentWorkspace.save(); err != nil {
+			return fmt.Errorf("failed to store config: %w", err)
 		}
+		fmt.Printf("OK\n")
+		return nil
 	},
 }
 
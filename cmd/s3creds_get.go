@@ -22,7 +22,6 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/dip-software/go-dip-api/s3creds"
@@ -36,20 +35,18 @@ var s3credsGetCmd = &cobra.Command{
 	Aliases: []string{"g"},
 	Short:   "Get S3 Credentials",
 	Long:    `Gets S3 Credentials for the given configuration.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := getCredentialsClient(cmd, args)
 		if err != nil {
-			fmt.Printf("error initializing S3 Credentials client: %v\n", err)
-			return
+			return fmt.Errorf("error initializing S3 Credentials client: %w", err)
 		}
 		access, _, err := client.Access.GetAccess(&s3creds.GetAccessOptions{
 			ProductKey: &currentWorkspace.S3CredsProductKey,
 		})
 		if err != nil {
-			fmt.Printf("Error retrieving credentials: %v\n", err)
+			return fmt.Errorf("error retrieving credentials: %w", err)
 		}
-		data, _ := json.Marshal(access)
-		fmt.Println(pretty(data))
+		return printObject(access)
 	},
 }
 
@@ -31,8 +31,8 @@ var s3credsPoliciesCmd = &cobra.Command{
 	Aliases: []string{"p"},
 	Short:   "Manage S3 Credentials policies",
 	Long:    `Manages S3 Credentials policies.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
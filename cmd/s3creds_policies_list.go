@@ -22,11 +22,9 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 
-	"github.com/cheynewallace/tabby"
 	"github.com/dip-software/go-dip-api/s3creds"
 	"github.com/spf13/cobra"
 )
@@ -37,36 +35,31 @@ var s3credsPoliciesListCmd = &cobra.Command{
 	Aliases: []string{"l", "li"},
 	Short:   "List S3 Credentials policies",
 	Long:    `Lists know S3 Credentials policies.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := getCredentialsClient(cmd, args)
 		if err != nil {
-			fmt.Printf("error initializing S3 Credentials client: %v\n", err)
-			return
+			return fmt.Errorf("error initializing S3 Credentials client: %w", err)
 		}
 		policies, _, err := client.Policy.GetPolicy(&s3creds.GetPolicyOptions{
 			ProductKey: &currentWorkspace.S3CredsProductKey,
 		})
 		if err != nil {
-			fmt.Printf("error retrieving policies list: %v\n", err)
-			return
+			return fmt.Errorf("error retrieving policies list: %w", err)
 		}
-		if jsonOut {
-			data, _ := json.Marshal(policies)
-			fmt.Printf("%s\n", data)
-			return
-		}
-		t := tabby.New()
-		t.AddHeader("policy id", "managing orgs", "groups", "resources")
+		rows := make([][]interface{}, 0)
 		for _, r := range policies {
-			t.AddLine(r.ID,
+			rows = append(rows, []interface{}{r.ID,
 				strings.Join(r.Conditions.ManagingOrganizations, ","),
 				strings.Join(r.Conditions.Groups, ","),
-				strings.Join(r.Allowed.Resources, ","))
+				strings.Join(r.Allowed.Resources, ",")})
+		}
+		if err := printOutput(policies, []string{"policy id", "managing orgs", "groups", "resources"}, rows); err != nil {
+			return err
 		}
-		t.Print()
-		if len(policies) == 0 {
+		if len(policies) == 0 && tableOutput() {
 			fmt.Printf("no policies found\n")
 		}
+		return nil
 	},
 }
 
@@ -16,15 +16,16 @@ func TestS3CredsCmd(t *testing.T) {
 		t.Error("expected command to have a short description")
 	}
 
-	if cmd.Run == nil {
-		t.Error("expected command to have a Run function")
+	if cmd.RunE == nil {
+		t.Error("expected command to have a RunE function")
 	}
 }
 
 func TestS3CredsCmdExecution(t *testing.T) {
 	cmd := s3credsCmd
-	cmd.Run = func(cmd *cobra.Command, args []string) {
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		// Simulate command execution
+		return nil
 	}
 
 	err := cmd.Execute()
@@ -0,0 +1,286 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+const (
+	secretStorePlain   = "plain"
+	secretStoreKeyring = "keyring"
+	secretStoreFile    = "file"
+
+	// secretRefPrefix marks a workspace field whose value lives in a secret store
+	secretRefPrefix = "secret:"
+
+	keyringService = "hs"
+)
+
+// secretStore holds workspace secrets outside of the workspace config file
+type secretStore interface {
+	Name() string
+	Load(workspace string, keys []string) (map[string]string, error)
+	Save(workspace string, secrets map[string]string) error
+	Delete(workspace string, keys []string) error
+}
+
+func getSecretStore(name string) (secretStore, error) {
+	switch name {
+	case secretStoreKeyring:
+		return &keyringStore{}, nil
+	case secretStoreFile:
+		return &fileStore{}, nil
+	case "", secretStorePlain:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown secret store '%s' (valid: %s, %s, %s)", name,
+		secretStorePlain, secretStoreKeyring, secretStoreFile)
+}
+
+// keyringStore uses the OS keyring: Keychain, Secret Service or Windows Credential Manager.
+// Each secret is stored as a separate entry to stay below platform size limits.
+type keyringStore struct{}
+
+func (k *keyringStore) Name() string {
+	return secretStoreKeyring
+}
+
+func (k *keyringStore) Load(workspace string, keys []string) (map[string]string, error) {
+	secrets := make(map[string]string)
+	for _, key := range keys {
+		value, err := keyring.Get(keyringService, workspace+"/"+key)
+		if errors.Is(err, keyring.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("keyring: %w", err)
+		}
+		secrets[key] = value
+	}
+	return secrets, nil
+}
+
+func (k *keyringStore) Save(workspace string, secrets map[string]string) error {
+	for key, value := range secrets {
+		if value == "" {
+			if err := k.Delete(workspace, []string{key}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := keyring.Set(keyringService, workspace+"/"+key, value); err != nil {
+			return fmt.Errorf("keyring: %w", err)
+		}
+	}
+	return nil
+}
+
+func (k *keyringStore) Delete(workspace string, keys []string) error {
+	for _, key := range keys {
+		err := keyring.Delete(keyringService, workspace+"/"+key)
+		if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			return fmt.Errorf("keyring: %w", err)
+		}
+	}
+	return nil
+}
+
+// fileStore keeps secrets in a passphrase encrypted file next to the workspace config.
+// The passphrase is read from HS_PASSPHRASE or prompted for when running interactively.
+type fileStore struct{}
+
+type encryptedSecrets struct {
+	Version int    `json:"v"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+var cachedPassphrase []byte
+
+func (f *fileStore) Name() string {
+	return secretStoreFile
+}
+
+func (f *fileStore) secretsFile(workspace string) string {
+	return filepath.Join(workspaceRoot(), workspace+".secrets.json")
+}
+
+func (f *fileStore) Load(workspace string, _ []string) (map[string]string, error) {
+	return f.read(workspace)
+}
+
+func (f *fileStore) Save(workspace string, secrets map[string]string) error {
+	stored := make(map[string]string)
+	for key, value := range secrets {
+		if value != "" {
+			stored[key] = value
+		}
+	}
+	if len(stored) == 0 {
+		return f.Delete(workspace, nil)
+	}
+	return f.write(workspace, stored)
+}
+
+func (f *fileStore) Delete(workspace string, _ []string) error {
+	err := os.Remove(f.secretsFile(workspace))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (f *fileStore) read(workspace string) (map[string]string, error) {
+	secrets := make(map[string]string)
+	data, err := os.ReadFile(f.secretsFile(workspace))
+	if os.IsNotExist(err) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var enc encryptedSecrets
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, fmt.Errorf("corrupt secrets file: %w", err)
+	}
+	passphrase, err := secretsPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	return decryptSecrets(passphrase, []byte(workspace), &enc)
+}
+
+func (f *fileStore) write(workspace string, secrets map[string]string) error {
+	passphrase, err := secretsPassphrase()
+	if err != nil {
+		return err
+	}
+	enc, err := encryptSecrets(passphrase, []byte(workspace), secrets)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(enc)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(f.secretsFile(workspace), data, 0600)
+}
+
+// encryptSecrets seals secrets with a key derived from passphrase, binding them to context
+func encryptSecrets(passphrase, context []byte, secrets map[string]string) (*encryptedSecrets, error) {
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	enc := &encryptedSecrets{
+		Version: 1,
+		Salt:    make([]byte, 16),
+	}
+	if _, err := rand.Read(enc.Salt); err != nil {
+		return nil, err
+	}
+	gcm, err := secretsCipher(passphrase, enc.Salt)
+	if err != nil {
+		return nil, err
+	}
+	enc.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(enc.Nonce); err != nil {
+		return nil, err
+	}
+	enc.Data = gcm.Seal(nil, enc.Nonce, plain, context)
+	return enc, nil
+}
+
+func decryptSecrets(passphrase, context []byte, enc *encryptedSecrets) (map[string]string, error) {
+	gcm, err := secretsCipher(passphrase, enc.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, enc.Nonce, enc.Data, context)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt secrets, wrong passphrase?")
+	}
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+func secretsCipher(passphrase, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func secretsPassphrase() ([]byte, error) {
+	if cachedPassphrase != nil {
+		return cachedPassphrase, nil
+	}
+	passphrase, err := readPassphrase("HS_PASSPHRASE", "Workspace passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	cachedPassphrase = passphrase
+	return cachedPassphrase, nil
+}
+
+// readPassphrase reads a passphrase from env or prompts for it when running interactively
+func readPassphrase(env, prompt string) ([]byte, error) {
+	if value := os.Getenv(env); value != "" {
+		return []byte(value), nil
+	}
+	if !term.IsTerminal(int(syscall.Stdin)) {
+		return nil, fmt.Errorf("no passphrase available, set %s", env)
+	}
+	fmt.Fprintf(os.Stderr, "%s", prompt)
+	passphrase, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Fprintf(os.Stderr, "\n")
+	if err != nil {
+		return nil, err
+	}
+	if len(strings.TrimSpace(string(passphrase))) == 0 {
+		return nil, fmt.Errorf("empty passphrase")
+	}
+	return passphrase, nil
+}
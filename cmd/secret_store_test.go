@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/zalando/go-keyring"
+)
+
+func TestEncryptSecretsRoundTrip(t *testing.T) {
+	secrets := map[string]string{"IAMAccessToken": "access", "IAMRefreshToken": "refresh"}
+	enc, err := encryptSecrets([]byte("passphrase"), []byte("dev"), secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := decryptSecrets([]byte("passphrase"), []byte("dev"), enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decrypted) != len(secrets) || decrypted["IAMAccessToken"] != "access" || decrypted["IAMRefreshToken"] != "refresh" {
+		t.Errorf("unexpected secrets after round trip: %v", decrypted)
+	}
+	other, err := encryptSecrets([]byte("passphrase"), []byte("dev"), secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(other.Salt) == string(enc.Salt) || string(other.Nonce) == string(enc.Nonce) {
+		t.Error("expected a fresh salt and nonce per encryption")
+	}
+}
+
+func TestDecryptSecretsRejectsWrongPassphraseAndTampering(t *testing.T) {
+	enc, err := encryptSecrets([]byte("passphrase"), []byte("dev"), map[string]string{"IAMAccessToken": "access"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decryptSecrets([]byte("wrong"), []byte("dev"), enc); err == nil {
+		t.Error("expected error for a wrong passphrase")
+	}
+	if _, err := decryptSecrets([]byte("passphrase"), []byte("prod"), enc); err == nil {
+		t.Error("expected error when secrets are moved to another workspace")
+	}
+	enc.Data[0] ^= 0xff
+	if _, err := decryptSecrets([]byte("passphrase"), []byte("dev"), enc); err == nil {
+		t.Error("expected error for tampered data")
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("HS_PASSPHRASE", "passphrase")
+	homedir.DisableCache = true
+	defer func() { homedir.DisableCache = false }()
+	cachedPassphrase = nil
+	defer func() { cachedPassphrase = nil }()
+	if err := os.MkdirAll(workspaceRoot(), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &fileStore{}
+	secrets, err := store.Load("dev", secretKeys())
+	if err != nil || len(secrets) != 0 {
+		t.Fatalf("expected no secrets without a secrets file, got %v %v", secrets, err)
+	}
+	if err := store.Save("dev", map[string]string{"IAMAccessToken": "access", "UAAAccessToken": ""}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(store.secretsFile("dev"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected secrets file mode 0600, got %o", info.Mode().Perm())
+	}
+	secrets, err = store.Load("dev", secretKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 1 || secrets["IAMAccessToken"] != "access" {
+		t.Errorf("unexpected secrets: %v", secrets)
+	}
+
+	cachedPassphrase = []byte("wrong")
+	if _, err := store.Load("dev", secretKeys()); err == nil {
+		t.Error("expected error for a wrong passphrase")
+	}
+	cachedPassphrase = nil
+
+	if err := store.Save("dev", map[string]string{"IAMAccessToken": ""}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(store.secretsFile("dev")); !os.IsNotExist(err) {
+		t.Error("expected secrets file to be removed when no secrets remain")
+	}
+}
+
+func TestKeyringStore(t *testing.T) {
+	keyring.MockInit()
+	store := &keyringStore{}
+	secrets, err := store.Load("dev", []string{"IAMAccessToken"})
+	if err != nil || len(secrets) != 0 {
+		t.Fatalf("expected missing entries to be skipped, got %v %v", secrets, err)
+	}
+	if err := store.Save("dev", map[string]string{"IAMAccessToken": "access", "IAMRefreshToken": "refresh"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save("dev", map[string]string{"IAMRefreshToken": ""}); err != nil {
+		t.Fatal(err)
+	}
+	secrets, err = store.Load("dev", []string{"IAMAccessToken", "IAMRefreshToken"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 1 || secrets["IAMAccessToken"] != "access" {
+		t.Errorf("unexpected secrets: %v", secrets)
+	}
+	if err := store.Delete("dev", secretKeys()); err != nil {
+		t.Errorf("expected delete of missing entries to succeed: %v", err)
+	}
+}
+
+func TestGetSecretStore(t *testing.T) {
+	for _, name := range []string{"", secretStorePlain} {
+		if store, err := getSecretStore(name); store != nil || err != nil {
+			t.Errorf("%q: expected plain storage, got %v %v", name, store, err)
+		}
+	}
+	if _, err := getSecretStore("vault"); err == nil {
+		t.Error("expected error for an unknown store")
+	}
+}
+
+func TestLoadSecretsLocksWhenStoreUnavailable(t *testing.T) {
+	keyring.MockInitWithError(errors.New("no secret service"))
+	defer keyring.MockInit()
+	w := &workspaceConfig{Name: "dev", SecretStore: secretStoreKeyring}
+	w.IAMAccessToken = secretRefPrefix + secretStoreKeyring
+	if err := w.loadSecrets(); err == nil {
+		t.Fatal("expected error when the keyring is unavailable")
+	}
+	if w.IAMAccessToken != "" {
+		t.Error("expected secret reference to be cleared")
+	}
+	if _, err := w.marshal(); err == nil {
+		t.Error("expected a locked workspace to refuse saving over its stored secrets")
+	}
+
+	keyring.MockInit()
+	w.IAMAccessToken = secretRefPrefix + secretStoreKeyring
+	if err := w.loadSecrets(); err != nil {
+		t.Fatalf("expected missing keyring entries to load as empty: %v", err)
+	}
+	if _, err := w.marshal(); err != nil {
+		t.Errorf("expected workspace to be unlocked: %v", err)
+	}
+}
+
+func TestLoadedSecretReferencesStayLockedUntilResolved(t *testing.T) {
+	testWorkspaceRoot(t)
+	keyring.MockInit()
+	saveTestWorkspace(t, &workspaceConfig{Name: "dev", SecretStore: secretStoreKeyring, IAMAccessToken: "access"})
+
+	w := loadTestWorkspace(t, "dev")
+	if !w.secretsLocked || w.IAMAccessToken != secretRefPrefix+secretStoreKeyring {
+		t.Fatalf("expected a locked secret reference, got %v %q", w.secretsLocked, w.IAMAccessToken)
+	}
+	if err := w.save(); err == nil {
+		t.Error("expected a workspace with unresolved references to refuse saving")
+	}
+
+	currentWorkspace = w
+	defer func() { currentWorkspace = nil }()
+	unlockWorkspaceSecrets()
+	if w.secretsLocked || w.IAMAccessToken != "access" {
+		t.Errorf("expected resolved secrets, got %v %q", w.secretsLocked, w.IAMAccessToken)
+	}
+	saveTestWorkspace(t, w)
+}
@@ -22,11 +22,8 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
-	"github.com/cheynewallace/tabby"
-
 	"github.com/dip-software/go-dip-api/config"
 
 	"github.com/spf13/cobra"
@@ -38,40 +35,33 @@ var servicesCmd = &cobra.Command{
 	Aliases: []string{"s"},
 	Short:   "Retrieve service information",
 	Long:    `Retrieves service information.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		config, err := getConfig(cmd)
 		if err != nil {
-			fmt.Printf("failed to get config: %v\n", err)
-			return
+			return fmt.Errorf("failed to get config: %w", err)
 		}
 		services := config.Services()
-		if len(services) == 0 {
-			if jsonOut {
-				fmt.Printf("[]\n")
-				return
-			}
+		if len(services) == 0 && tableOutput() {
 			fmt.Printf("no services found\n")
-			return
-		}
-		if jsonOut {
-			data, _ := json.Marshal(services)
-			fmt.Printf("%s\n", data)
-			return
+			return nil
 		}
-		t := tabby.New()
-		t.AddHeader("service", "host", "url", "domain")
+		rows := make([][]interface{}, 0)
 		for _, i := range services {
 			host := config.Service(i).Host
 			url := config.Service(i).URL
 			domain := config.Service(i).Domain
-			t.AddLine(i,
+			rows = append(rows, []interface{}{i,
 				host,
 				url,
-				domain)
+				domain})
 		}
-		t.Print()
-
-		fmt.Printf("\n")
+		if err := printOutput(services, []string{"service", "host", "url", "domain"}, rows); err != nil {
+			return err
+		}
+		if tableOutput() {
+			fmt.Printf("\n")
+		}
+		return nil
 	},
 }
 
@@ -22,11 +22,8 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
-	"github.com/cheynewallace/tabby"
-
 	"github.com/spf13/cobra"
 )
 
@@ -36,34 +33,27 @@ var servicesRegionsCmd = &cobra.Command{
 	Aliases: []string{"r"},
 	Short:   "Retrieve region information",
 	Long:    `Retrieves region information.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		config, err := getConfig(cmd)
 		if err != nil {
-			fmt.Printf("failed to get config: %v\n", err)
-			return
+			return fmt.Errorf("failed to get config: %w", err)
 		}
 		regions := config.Regions()
-		if len(regions) == 0 {
-			if jsonOut {
-				fmt.Printf("[]\n")
-				return
-			}
+		if len(regions) == 0 && tableOutput() {
 			fmt.Printf("no regions found\n")
-			return
-		}
-		if jsonOut {
-			data, _ := json.Marshal(regions)
-			fmt.Printf("%s\n", data)
-			return
+			return nil
 		}
-		t := tabby.New()
-		t.AddHeader("regions")
+		rows := make([][]interface{}, 0)
 		for _, r := range regions {
-			t.AddLine(r)
+			rows = append(rows, []interface{}{r})
 		}
-		t.Print()
-
-		fmt.Printf("\n")
+		if err := printOutput(regions, []string{"regions"}, rows); err != nil {
+			return err
+		}
+		if tableOutput() {
+			fmt.Printf("\n")
+		}
+		return nil
 	},
 }
 
@@ -41,7 +41,7 @@ var signedRequestCmd = &cobra.Command{
 	Short:   "Perform a signed request",
 	Long:    `Perform a request that is protected by the HSDP API signing algorithm`,
 	Aliases: []string{"sr"},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		key, _ := cmd.Flags().GetString("key")
 		secret, _ := cmd.Flags().GetString("secret")
 		method, _ := cmd.Flags().GetString("method")
@@ -50,13 +50,11 @@ var signedRequestCmd = &cobra.Command{
 		debug, _ := cmd.Flags().GetBool("debug")
 		apiVersion, _ := cmd.Flags().GetString("api-version")
 		if key == "" || secret == "" {
-			fmt.Println("required key or secret missing")
-			return
+			return invalidInput("required key or secret missing")
 		}
 		s, err := signer.New(key, secret)
 		if err != nil {
-			fmt.Printf("error creating signer: %v\n", err)
-			return
+			return fmt.Errorf("error creating signer: %w", err)
 		}
 		var bodyReader io.Reader
 		if _, err := os.Stat(data); os.IsNotExist(err) {
@@ -72,8 +70,7 @@ var signedRequestCmd = &cobra.Command{
 		}
 		err = s.SignRequest(req)
 		if err != nil {
-			fmt.Printf("error signing: %v\n", err)
-			return
+			return fmt.Errorf("error signing: %w", err)
 		}
 		client := http.DefaultClient
 		if debug {
@@ -86,20 +83,19 @@ var signedRequestCmd = &cobra.Command{
 			fmt.Printf("%s\n", string(dumped))
 		}
 		if err != nil {
-			fmt.Printf("error on request: %v\n", err)
-			return
+			return fmt.Errorf("error on request: %w", err)
 		}
 		if resp == nil || resp.Body == nil {
-			fmt.Printf("response error\n")
+			return fmt.Errorf("response error")
 		}
 		respData, err := io.ReadAll(resp.Body)
 		if err != nil {
-			fmt.Printf("error reading body: %v\n", err)
-			return
+			return fmt.Errorf("error reading body: %w", err)
 		}
 		if !debug {
 			fmt.Printf("%v", string(respData))
 		}
+		return nil
 	},
 }
 
@@ -31,8 +31,8 @@ var tfstateCmd = &cobra.Command{
 	Use:     "tfstate",
 	Short:   "TFSTATE related commands",
 	Long:    `Supports the TFSTATE service.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
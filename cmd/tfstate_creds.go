@@ -36,7 +36,7 @@ var tfstateCredsCmd = &cobra.Command{
 	Use:   "creds",
 	Short: "Set credentials for tfstate",
 	Long:  `Sets the credentials to use for the tfstate instance.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var err error
 		username, _ := cmd.Flags().GetString("username")
 		password, _ := cmd.Flags().GetString("password")
@@ -45,8 +45,7 @@ var tfstateCredsCmd = &cobra.Command{
 			address, err = tfstateAddress(currentWorkspace.TFStateInstanceURL)
 		}
 		if err != nil {
-			fmt.Printf("error reading address: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error reading address: %w", err)
 		}
 		data, err := base64.StdEncoding.DecodeString(currentWorkspace.TFStateCreds)
 		if err == nil {
@@ -63,10 +62,10 @@ var tfstateCredsCmd = &cobra.Command{
 		username, password, err = credentials(username, password)
 		fmt.Printf("\n")
 		if err != nil {
-			fmt.Printf("error reading credentials: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error reading credentials: %w", err)
 		}
 		persistTFState(address, base64.StdEncoding.EncodeToString([]byte(username+":"+password)))
+		return nil
 	},
 }
 
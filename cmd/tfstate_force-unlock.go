@@ -40,18 +40,16 @@ var tfstateForceUnlockCmd = &cobra.Command{
 	Use:   "force-unlock",
 	Short: "Force unlock a locked state",
 	Long:  `Force unlocks a locked state.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		key, _ := cmd.Flags().GetString("key")
 		lockID, _ := cmd.Flags().GetString("id")
 		key, err := tfstateKey(key)
 		if err != nil {
-			fmt.Printf("error reading key: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error reading key: %w", err)
 		}
 		lockID, err = tfstateLockID(lockID)
 		if err != nil {
-			fmt.Printf("error reading lock ID: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error reading lock ID: %w", err)
 		}
 		tfstateEndpoint := currentWorkspace.TFStateInstanceURL + "/" + key
 		httpClient := &http.Client{
@@ -70,16 +68,19 @@ var tfstateForceUnlockCmd = &cobra.Command{
 		req.Header.Set("Content-Type", "application/json")
 		resp, err := httpClient.Do(req)
 		if err != nil {
-			fmt.Printf("error fetch list: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error unlocking state: %w", err)
 		}
 		defer resp.Body.Close()
 		response, _ := io.ReadAll(resp.Body)
-		if jsonOut {
+		if !tableOutput() {
 			fmt.Printf("%s\n", string(response))
-			return
+		} else {
+			fmt.Printf("STATUS %d\n", resp.StatusCode)
 		}
-		fmt.Printf("STATUS %d\n", resp.StatusCode)
+		if resp.StatusCode >= http.StatusBadRequest {
+			return statusError(resp.StatusCode, "force-unlock failed with status %d", resp.StatusCode)
+		}
+		return nil
 	},
 }
 
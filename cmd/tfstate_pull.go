@@ -10,7 +10,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -24,16 +23,14 @@ var tfstatePullCmd = &cobra.Command{
 	Use:   "pull",
 	Short: "Pulls a specific state version",
 	Long:  `Pulls a specific state version from the backend`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		key, _ := cmd.Flags().GetString("key")
 		key, err := tfstateKey(key)
 		if err != nil {
-			fmt.Printf("error reading key: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error reading key: %w", err)
 		}
 		if len(args) < 1 {
-			fmt.Printf("specify version to retrieve as the only argument\n")
-			os.Exit(1)
+			return invalidInput("specify version to retrieve as the only argument")
 		}
 		version := args[0]
 
@@ -52,29 +49,24 @@ var tfstatePullCmd = &cobra.Command{
 		req.Header.Set("Authorization", "Basic "+currentWorkspace.TFStateCreds)
 		resp, err := httpClient.Do(req)
 		if err != nil {
-			fmt.Printf("error fetch list: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error fetching version: %w", err)
 		}
 		defer resp.Body.Close()
 		var state map[string]interface{}
 
 		switch resp.StatusCode {
 		case http.StatusNoContent:
-			fmt.Printf("{}\n")
-			os.Exit(1)
+			return notFound("version %s not found", version)
 		case http.StatusOK:
 			break
 		default:
-			fmt.Printf("Unexpected status: %d\n", resp.StatusCode)
-			os.Exit(1)
+			return statusError(resp.StatusCode, "unexpected status: %d", resp.StatusCode)
 		}
 		if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
-			fmt.Printf("error decoding versions body: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error decoding versions body: %w", err)
 		}
 
-		data, _ := json.Marshal(state)
-		fmt.Printf("%s\n", pretty(data))
+		return printObject(state)
 	},
 }
 
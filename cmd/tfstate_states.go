@@ -25,7 +25,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -35,7 +34,7 @@ var tfstateStatesCmd = &cobra.Command{
 	Use:   "states",
 	Short: "Lists all known state keys",
 	Long:  `Lists all known state keys.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		tfstatesEndpoint := currentWorkspace.TFStateInstanceURL + "/states"
 		httpClient := &http.Client{
 			Transport: &http.Transport{
@@ -46,26 +45,29 @@ var tfstateStatesCmd = &cobra.Command{
 		req.Header.Set("Authorization", "Basic "+currentWorkspace.TFStateCreds)
 		resp, err := httpClient.Do(req)
 		if err != nil {
-			fmt.Printf("error fetch list: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error fetch list: %w", err)
 		}
 		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return statusError(resp.StatusCode, "error fetching states: status %d", resp.StatusCode)
+		}
 		states := make([]string, 0)
 
 		if err := json.NewDecoder(resp.Body).Decode(&states); err != nil {
-			fmt.Printf("error decoding states body: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error decoding states body: %w", err)
 		}
-		if jsonOut {
-			data, _ := json.Marshal(states)
-			fmt.Printf("%s\n", string(data))
-			return
+		if tableOutput() {
+			fmt.Printf("Found %d state keys\n", len(states))
+			for _, v := range states {
+				fmt.Printf("%s\n", v)
+			}
+			return nil
 		}
-
-		fmt.Printf("Found %d state keys\n", len(states))
+		rows := make([][]interface{}, 0)
 		for _, v := range states {
-			fmt.Printf("%s\n", v)
+			rows = append(rows, []interface{}{v})
 		}
+		return printOutput(states, []string{"state key"}, rows)
 	},
 }
 
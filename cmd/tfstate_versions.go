@@ -37,12 +37,11 @@ var tfstateVersionsCmd = &cobra.Command{
 	Use:   "versions",
 	Short: "List versions of a state key",
 	Long:  `Lists available versions of a state key.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		key, _ := cmd.Flags().GetString("key")
 		key, err := tfstateKey(key)
 		if err != nil {
-			fmt.Printf("error reading key: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error reading key: %w", err)
 		}
 		tfstateEndpoint := currentWorkspace.TFStateInstanceURL + "/versions?ref=" + key
 		httpClient := &http.Client{
@@ -54,26 +53,29 @@ var tfstateVersionsCmd = &cobra.Command{
 		req.Header.Set("Authorization", "Basic "+currentWorkspace.TFStateCreds)
 		resp, err := httpClient.Do(req)
 		if err != nil {
-			fmt.Printf("error fetch list: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error fetch list: %w", err)
 		}
 		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return statusError(resp.StatusCode, "error fetching versions: status %d", resp.StatusCode)
+		}
 		versions := make([]string, 0)
 
 		if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
-			fmt.Printf("error decoding versions body: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error decoding versions body: %w", err)
 		}
-		if jsonOut {
-			data, _ := json.Marshal(versions)
-			fmt.Printf("%s\n", string(data))
-			return
+		if tableOutput() {
+			fmt.Printf("Found %d versions\n", len(versions))
+			for _, v := range versions {
+				fmt.Printf("%s\n", v)
+			}
+			return nil
 		}
-
-		fmt.Printf("Found %d versions\n", len(versions))
+		rows := make([][]interface{}, 0)
 		for _, v := range versions {
-			fmt.Printf("%s\n", v)
+			rows = append(rows, []interface{}{v})
 		}
+		return printOutput(versions, []string{"version"}, rows)
 	},
 }
 
@@ -30,11 +30,14 @@ var uaaCmd = &cobra.Command{
 	Use:   "uaa",
 	Short: "UAA related services",
 	Long:  `UAA related services.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(uaaCmd)
 }
+
+// errUAALoginRequired is returned by commands which need a UAA session
+var errUAALoginRequired = authRequired("login to UAA first using: hs uaa login")
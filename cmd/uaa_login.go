@@ -41,7 +41,7 @@ var uaaLoginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Login to UAA",
 	Long:  `Login to the regional UAA endpoint.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		region, _ := cmd.Flags().GetString("region")
 		if region == "" {
 			region = currentWorkspace.DefaultRegion
@@ -51,24 +51,22 @@ var uaaLoginCmd = &cobra.Command{
 		username, password, err := credentials(username, password)
 		fmt.Printf("\n")
 		if err != nil {
-			fmt.Printf("error logging in: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error logging in: %w", err)
 		}
 		consoleClient, err := console.NewClient(http.DefaultClient, &console.Config{
 			Region: region,
 		})
 		if err != nil {
-			fmt.Printf("error initializing CONSOLE client: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("error initializing CONSOLE client: %w", err)
 		}
 		err = consoleClient.Login(username, password)
 		if err != nil {
-			fmt.Printf("error logging in: %v\n", err)
-			os.Exit(1)
+			return authRequired("error logging in: %w", err)
 		}
 		persistUAACredentials(consoleClient)
 		token, _ := consoleClient.Token()
 		fmt.Printf("%v\n", token)
+		return nil
 	},
 }
 
@@ -1,8 +1,16 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/term"
 )
 
 func pretty(data []byte) string {
@@ -10,3 +18,34 @@ func pretty(data []byte) string {
 	_ = json.Indent(&prettyJSON, data, "", "  ")
 	return prettyJSON.String()
 }
+
+// shortDuration formats d compactly, e.g. 45s, 12m, 3h4m or 2d5h
+func shortDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+}
+
+// confirm asks the user to confirm a destructive action. yes skips the
+// question, which is required when not running interactively.
+func confirm(question string, yes bool) error {
+	if yes {
+		return nil
+	}
+	if !term.IsTerminal(int(syscall.Stdin)) {
+		return invalidInput("%s: not running interactively, use --yes to confirm", question)
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return fmt.Errorf("aborted")
+}
@@ -50,8 +50,8 @@ Workspaces are a core concept of hs. You can setup multiple workspaces
 each having their own regional and environment based configuration. 
 This is very convenient if you have global deployments or are working with 
 mulitple customers and need to context switch frequently.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		_ = cmd.Help()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
 	},
 }
 
@@ -60,12 +60,15 @@ type workspaceConfig struct {
 	Name                  string      `json:"-"`
 	Version               int         `json:"Version"`
 	DefaultRegion         string      `json:"DefaultRegion"`
-	DefaultEnvironment    string      `json:"DefaultEnvironemnt"`
+	DefaultEnvironment    string      `json:"DefaultEnvironment"`
 	IAMUserUUID           string      `json:"IAMUserUUID"`
 	IAMAccessToken        string      `json:"IAMAccessToken"`
 	IAMAccessTokenExpires int64       `json:"IAMAccessTokenExpires"`
 	IAMRefreshToken       string      `json:"IAMRefreshToken"`
 	IAMIDToken            string      `json:"IAMIDToken"`
+	IAMServiceKey         string      `json:"IAMServiceKey,omitempty"`
+	IAMClientID           string      `json:"IAMClientID,omitempty"`
+	IAMClientSecret       string      `json:"IAMClientSecret,omitempty"`
 	IAMRegion             string      `json:"IAMRegion"`
 	IAMEnvironment        string      `json:"IAMEnvironment"`
 	IAMSelectedOrg        string      `json:"IAMSelectedOrg"`
@@ -79,6 +82,57 @@ type workspaceConfig struct {
 	PKILogicalPath        string      `json:"PKILogicalPath"`
 	TFStateCreds          string      `json:"TFStateCreds"`
 	TFStateInstanceURL    string      `json:"TFStateInstanceURL"`
+	SecretStore           string      `json:"SecretStore,omitempty"`
+	secretsLocked         bool
+	// loadedIAM and loadedUAA hold the tokens as loaded from or last saved to
+	// disk, nil for workspaces which were not loaded from disk
+	loadedIAM *loadedToken
+	loadedUAA *loadedToken
+}
+
+// loadedToken identifies an access token so save can tell whether the caller replaced it
+type loadedToken struct {
+	token   string
+	expires int64
+}
+
+func (w *workspaceConfig) iamToken() loadedToken {
+	return loadedToken{w.IAMAccessToken, w.IAMAccessTokenExpires}
+}
+
+func (w *workspaceConfig) uaaToken() loadedToken {
+	return loadedToken{w.UAAToken, w.UAAAccessTokenExpires}
+}
+
+// rememberTokens records the current tokens as the ones on disk
+func (w *workspaceConfig) rememberTokens() {
+	iam, uaa := w.iamToken(), w.uaaToken()
+	w.loadedIAM, w.loadedUAA = &iam, &uaa
+}
+
+// secretFields maps the JSON names of all credential fields to their values
+func (w *workspaceConfig) secretFields() map[string]*string {
+	return map[string]*string{
+		"IAMAccessToken":  &w.IAMAccessToken,
+		"IAMRefreshToken": &w.IAMRefreshToken,
+		"IAMIDToken":      &w.IAMIDToken,
+		"IAMServiceKey":   &w.IAMServiceKey,
+		"IAMClientSecret": &w.IAMClientSecret,
+		"UAAAccessToken":  &w.UAAToken,
+		"UAARefreshToken": &w.UAARefreshToken,
+		"UAAIDToken":      &w.UAAIDToken,
+		"TFStateCreds":    &w.TFStateCreds,
+		"IronToken":       &w.IronConfig.Token,
+		"IronPassword":    &w.IronConfig.Password,
+	}
+}
+
+func secretKeys() []string {
+	keys := make([]string, 0)
+	for key := range (&workspaceConfig{}).secretFields() {
+		keys = append(keys, key)
+	}
+	return keys
 }
 
 func (w *workspaceConfig) iamExpireTime() *time.Time {
@@ -148,7 +202,19 @@ func (w *workspaceConfig) list() ([]string, string, error) {
 	for _, l := range list {
 		workspaceList = append(workspaceList, workspaceName(l))
 	}
-	return workspaceList, currentWorkspaceName(), nil
+	return workspaceList, activeWorkspaceName(), nil
+}
+
+// activeWorkspaceName returns the workspace for this invocation: the --workspace
+// flag, then HS_WORKSPACE and finally the current workspace link
+func activeWorkspaceName() string {
+	if workspaceOverride != "" {
+		return workspaceOverride
+	}
+	if env := os.Getenv("HS_WORKSPACE"); env != "" {
+		return env
+	}
+	return currentWorkspaceName()
 }
 
 func workspaceName(file string) string {
@@ -186,62 +252,305 @@ func (w *workspaceConfig) delete(workspace string) error {
 	if workspace == currentWorkspaceName() {
 		_ = w.setDefault("default")
 	}
-	return os.Remove(w.configFile(workspace))
+	return removeWorkspace(workspace)
+}
+
+// removeWorkspace removes the config file and stored secrets of a workspace
+func removeWorkspace(workspace string) error {
+	if target, err := loadWorkspaceConfig(workspace); err == nil {
+		if store, err := getSecretStore(target.SecretStore); err == nil && store != nil {
+			if err := store.Delete(workspace, secretKeys()); err != nil {
+				return fmt.Errorf("removing secrets: %w", err)
+			}
+		}
+	}
+	return os.Remove((&workspaceConfig{}).configFile(workspace))
+}
+
+// workspaceExists returns true if a config file exists for workspace
+func workspaceExists(workspace string) bool {
+	_, err := os.Stat((&workspaceConfig{}).configFile(workspace))
+	return err == nil
+}
+
+// validateWorkspaceName rejects names which would put the workspace files
+// outside of the workspace root
+func validateWorkspaceName(name string) error {
+	if name == "" || name == "." || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return invalidInput("invalid workspace name '%s'", name)
+	}
+	return nil
+}
+
+// cloneWorkspace copies workspace src to dst including its secrets unless dropTokens is set
+func cloneWorkspace(src, dst string, dropTokens bool) error {
+	if err := validateWorkspaceName(dst); err != nil {
+		return err
+	}
+	if workspaceExists(dst) {
+		return fmt.Errorf("workspace %s already exists", dst)
+	}
+	source, err := loadWorkspaceConfig(src)
+	if err != nil {
+		return err
+	}
+	if err := source.loadSecrets(); err != nil {
+		return fmt.Errorf("loading secrets: %w", err)
+	}
+	source.Name = dst
+	// The copy is a new workspace, never merge tokens into it
+	source.loadedIAM, source.loadedUAA = nil, nil
+	if dropTokens {
+		for _, field := range source.secretFields() {
+			*field = ""
+		}
+		source.IAMAccessTokenExpires = 0
+		source.UAAAccessTokenExpires = 0
+		source.IAMUserUUID = ""
+	}
+	return source.save()
 }
 
 func (w *workspaceConfig) saveWithIAM(client *iam.Client) error {
-	token, _ := client.Token()
+	token, err := client.Token()
+	if err != nil {
+		return err
+	}
 	w.IAMAccessToken = token
 	w.IAMRefreshToken = client.RefreshToken()
+	w.IAMIDToken = client.IDToken()
 	w.IAMAccessTokenExpires = client.Expires()
 	return w.save()
 }
+
 func (w *workspaceConfig) save() error {
 	w.Lock()
 	defer w.Unlock()
-	w.Version = 1
-	data, err := json.Marshal(w)
+	unlock, err := lockWorkspaces()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	w.mergeNewerTokens()
+	w.Version = workspaceVersion
+	data, err := w.marshal()
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(w.configFile(), data, 0600); err != nil {
+		return err
+	}
+	w.rememberTokens()
+	return nil
+}
+
+// mergeNewerTokens adopts tokens another hs process saved after this workspace
+// was loaded, so a stale copy never clobbers a newer token. Tokens are only
+// merged while they still equal the loaded ones: tokens set by a login,
+// refresh or import are always saved as-is.
+func (w *workspaceConfig) mergeNewerTokens() {
+	staleIAM := w.loadedIAM != nil && *w.loadedIAM == w.iamToken()
+	staleUAA := w.loadedUAA != nil && *w.loadedUAA == w.uaaToken()
+	if !staleIAM && !staleUAA {
+		return
+	}
+	// save holds the workspace lock already
+	onDisk, err := readWorkspaceConfig(w.Name, true)
+	if err != nil || onDisk.SecretStore != w.SecretStore {
+		return
+	}
+	newerIAM := staleIAM && onDisk.IAMAccessTokenExpires > w.IAMAccessTokenExpires
+	newerUAA := staleUAA && onDisk.UAAAccessTokenExpires > w.UAAAccessTokenExpires
+	if !newerIAM && !newerUAA {
+		return
+	}
+	if err := onDisk.loadSecrets(); err != nil {
+		return
+	}
+	if newerIAM {
+		w.IAMAccessToken = onDisk.IAMAccessToken
+		w.IAMRefreshToken = onDisk.IAMRefreshToken
+		w.IAMIDToken = onDisk.IAMIDToken
+		w.IAMAccessTokenExpires = onDisk.IAMAccessTokenExpires
+		// The tokens may belong to another identity than the stale copy
+		w.IAMServiceKey = onDisk.IAMServiceKey
+		w.IAMClientID = onDisk.IAMClientID
+		w.IAMClientSecret = onDisk.IAMClientSecret
+		w.IAMUserUUID = onDisk.IAMUserUUID
+		w.IAMRegion = onDisk.IAMRegion
+		w.IAMEnvironment = onDisk.IAMEnvironment
+	}
+	if newerUAA {
+		w.UAAToken = onDisk.UAAToken
+		w.UAARefreshToken = onDisk.UAARefreshToken
+		w.UAAIDToken = onDisk.UAAIDToken
+		w.UAAAccessTokenExpires = onDisk.UAAAccessTokenExpires
+	}
+}
+
+// marshal serializes the workspace. When a secret store is configured the
+// credential fields are written to the store and only references are kept.
+func (w *workspaceConfig) marshal() ([]byte, error) {
+	store, err := getSecretStore(w.SecretStore)
 	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return json.Marshal(w)
+	}
+	if w.secretsLocked {
+		return nil, fmt.Errorf("secrets of workspace %s are locked", w.Name)
+	}
+	fields := w.secretFields()
+	secrets := make(map[string]string)
+	for key, field := range fields {
+		secrets[key] = *field
+	}
+	if err := store.Save(w.Name, secrets); err != nil {
+		return nil, fmt.Errorf("saving secrets: %w", err)
+	}
+	for _, field := range fields {
+		if *field != "" {
+			*field = secretRefPrefix + store.Name()
+		}
+	}
+	data, err := json.Marshal(w)
+	for key, field := range fields {
+		*field = secrets[key]
+	}
+	return data, err
+}
+
+// loadSecrets resolves secret references from the configured secret store.
+// On failure the workspace is locked so it cannot overwrite the stored secrets.
+func (w *workspaceConfig) loadSecrets() error {
+	store, err := getSecretStore(w.SecretStore)
+	if err != nil || store == nil {
 		return err
 	}
-	if err := os.WriteFile(w.configFile(), data, 0600); err != nil {
+	// Loaded tokens which are references resolve to the stored values
+	iamLoaded := w.loadedIAM != nil && *w.loadedIAM == w.iamToken()
+	uaaLoaded := w.loadedUAA != nil && *w.loadedUAA == w.uaaToken()
+	fields := w.secretFields()
+	keys := make([]string, 0)
+	for key, field := range fields {
+		if strings.HasPrefix(*field, secretRefPrefix) {
+			keys = append(keys, key)
+			*field = ""
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	secrets, err := store.Load(w.Name, keys)
+	if err != nil {
+		w.secretsLocked = true
 		return err
 	}
+	for _, key := range keys {
+		*fields[key] = secrets[key]
+	}
+	if iamLoaded {
+		iam := w.iamToken()
+		w.loadedIAM = &iam
+	}
+	if uaaLoaded {
+		uaa := w.uaaToken()
+		w.loadedUAA = &uaa
+	}
+	w.secretsLocked = false
 	return nil
 }
 
+// unlockWorkspaceSecrets resolves the secret references of the current workspace
+// before a command runs. Workspaces are loaded with their references locked, so
+// a command which fails to unlock them cannot save references as secret values.
+func unlockWorkspaceSecrets() {
+	if err := currentWorkspace.loadSecrets(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: unable to load workspace secrets: %v\n", err)
+	}
+}
+
 func loadWorkspaceConfig(workspace string) (*workspaceConfig, error) {
+	return readWorkspaceConfig(workspace, false)
+}
+
+// readWorkspaceConfig loads workspace and upgrades an old format on disk. The
+// upgrade takes the workspace lock unless locked says the caller holds it.
+func readWorkspaceConfig(workspace string, locked bool) (*workspaceConfig, error) {
 	newTarget := &workspaceConfig{}
 	newTarget.Name = workspace
 	data, err := os.ReadFile(newTarget.configFile())
 	if err != nil {
 		return nil, err
 	}
-	err = json.Unmarshal(data, newTarget)
+	migrated, version, err := migrateWorkspace(data)
+	if err != nil {
+		return nil, err
+	}
+	if version != workspaceVersion && !locked {
+		unlock, err := lockWorkspaces()
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+		// Another hs process may have upgraded the file while waiting for the lock
+		if data, err = os.ReadFile(newTarget.configFile()); err != nil {
+			return nil, err
+		}
+		if migrated, version, err = migrateWorkspace(data); err != nil {
+			return nil, err
+		}
+	}
+	if version != workspaceVersion {
+		// Keep the original around in case the upgrade needs to be rolled back
+		backup := fmt.Sprintf("%s.v%d.bak", newTarget.configFile(), version)
+		if err := os.WriteFile(backup, data, 0600); err != nil {
+			return nil, fmt.Errorf("backing up workspace: %w", err)
+		}
+		if err := writeFileAtomic(newTarget.configFile(), migrated, 0600); err != nil {
+			return nil, fmt.Errorf("saving migrated workspace: %w", err)
+		}
+	}
+	err = json.Unmarshal(migrated, newTarget)
 	if err != nil {
 		return nil, err
 	}
 	newTarget.Name = workspace
+	newTarget.rememberTokens()
+	// Secret references stay locked until loadSecrets resolves them
+	for _, field := range newTarget.secretFields() {
+		if strings.HasPrefix(*field, secretRefPrefix) {
+			newTarget.secretsLocked = true
+		}
+	}
 	return newTarget, nil
 }
 
 func (w *workspaceConfig) setDefault(workspace string) error {
 	w.Lock()
 	defer w.Unlock()
-	current := filepath.Join(workspaceRoot(), "current")
-	if _, err := os.Lstat(current); err == nil {
-		if err := os.Remove(current); err != nil {
-			return err
-		}
+	unlock, err := lockWorkspaces()
+	if err != nil {
+		return err
 	}
+	defer unlock()
+	current := filepath.Join(workspaceRoot(), "current")
 	w.Name = workspace
 	if runtime.GOOS != "windows" {
-		if err := os.Symlink(w.configFile(), current); err != nil {
+		// Swap the link in a single rename so other processes never miss it
+		tmpLink := current + ".tmp"
+		_ = os.Remove(tmpLink)
+		if err := os.Symlink(w.configFile(), tmpLink); err != nil {
+			return err
+		}
+		if err := os.Rename(tmpLink, current); err != nil {
+			_ = os.Remove(tmpLink)
 			return err
 		}
 	} else {
 		// Windows
-		if err := os.WriteFile(current, []byte(w.configFile()), 0600); err != nil {
+		if err := writeFileAtomic(current, []byte(w.configFile()), 0600); err != nil {
 			return err
 		}
 	}
@@ -256,7 +565,7 @@ func ensureRoot() {
 		// Create
 		defaultWorkspace := &workspaceConfig{
 			Name:               "default",
-			Version:            1,
+			Version:            workspaceVersion,
 			DefaultRegion:      "us-east",
 			DefaultEnvironment: "client-test",
 		}
@@ -279,7 +588,7 @@ func ensureDefault() {
 	if os.IsNotExist(err) {
 		if runtime.GOOS != "windows" {
 			// Link to default
-			if err := os.Symlink("default.config.json", currentWorkspaceFile); err != nil {
+			if err := os.Symlink("default.config.json", currentWorkspaceFile); err != nil && !os.IsExist(err) {
 				fmt.Printf("Failed to set default workspace: %v\n", err)
 				os.Exit(1)
 			}
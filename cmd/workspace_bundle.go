@@ -0,0 +1,222 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dip-software/go-dip-api/iron"
+	"gopkg.in/yaml.v3"
+)
+
+const workspaceBundleKind = "hs-workspace"
+
+// bundleSecretsContext binds encrypted bundle secrets to their purpose
+var bundleSecretsContext = []byte(workspaceBundleKind)
+
+// workspaceBundle is the portable representation of a workspace
+type workspaceBundle struct {
+	Kind               string            `json:"kind"`
+	Version            int               `json:"version"`
+	Name               string            `json:"name"`
+	DefaultRegion      string            `json:"defaultRegion,omitempty"`
+	DefaultEnvironment string            `json:"defaultEnvironment,omitempty"`
+	IAMRegion          string            `json:"iamRegion,omitempty"`
+	IAMEnvironment     string            `json:"iamEnvironment,omitempty"`
+	IAMSelectedOrg     string            `json:"iamSelectedOrg,omitempty"`
+	IAMSelectedOrgName string            `json:"iamSelectedOrgName,omitempty"`
+	S3CredsProductKey  string            `json:"s3credsProductKey,omitempty"`
+	PKILogicalPath     string            `json:"pkiLogicalPath,omitempty"`
+	TFStateInstanceURL string            `json:"tfstateInstanceURL,omitempty"`
+	IronConfig         *iron.Config      `json:"ironConfig,omitempty"`
+	Secrets            *encryptedSecrets `json:"secrets,omitempty"`
+}
+
+func newWorkspaceBundle(w *workspaceConfig) *workspaceBundle {
+	bundle := &workspaceBundle{
+		Kind:               workspaceBundleKind,
+		Version:            1,
+		Name:               w.Name,
+		DefaultRegion:      w.DefaultRegion,
+		DefaultEnvironment: w.DefaultEnvironment,
+		IAMRegion:          w.IAMRegion,
+		IAMEnvironment:     w.IAMEnvironment,
+		IAMSelectedOrg:     w.IAMSelectedOrg,
+		IAMSelectedOrgName: w.IAMSelectedOrgName,
+		S3CredsProductKey:  w.S3CredsProductKey,
+		PKILogicalPath:     w.PKILogicalPath,
+		TFStateInstanceURL: w.TFStateInstanceURL,
+	}
+	if w.IronConfig.ProjectID != "" {
+		ironConfig := w.IronConfig
+		ironConfig.Token = ""
+		ironConfig.Password = ""
+		bundle.IronConfig = &ironConfig
+	}
+	return bundle
+}
+
+// workspace returns a new workspace holding the bundle settings
+func (b *workspaceBundle) workspace(name string) *workspaceConfig {
+	w := &workspaceConfig{
+		Name:               name,
+		DefaultRegion:      b.DefaultRegion,
+		DefaultEnvironment: b.DefaultEnvironment,
+		IAMRegion:          b.IAMRegion,
+		IAMEnvironment:     b.IAMEnvironment,
+		IAMSelectedOrg:     b.IAMSelectedOrg,
+		IAMSelectedOrgName: b.IAMSelectedOrgName,
+		S3CredsProductKey:  b.S3CredsProductKey,
+		PKILogicalPath:     b.PKILogicalPath,
+		TFStateInstanceURL: b.TFStateInstanceURL,
+	}
+	if b.IronConfig != nil {
+		w.IronConfig = *b.IronConfig
+	}
+	return w
+}
+
+// exportWorkspace bundles the settings of workspace name, including its
+// credentials encrypted with a passphrase when includeSecrets is set
+func exportWorkspace(name string, includeSecrets bool) (*workspaceBundle, error) {
+	workspace, err := loadWorkspaceConfig(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load workspace %s: %w", name, err)
+	}
+	bundle := newWorkspaceBundle(workspace)
+	if !includeSecrets {
+		return bundle, nil
+	}
+	if err := workspace.loadSecrets(); err != nil {
+		return nil, fmt.Errorf("unable to load workspace secrets: %w", err)
+	}
+	passphrase, err := readPassphrase("HS_BUNDLE_PASSPHRASE", "Bundle passphrase: ")
+	if err != nil {
+		return nil, fmt.Errorf("error reading passphrase: %w", err)
+	}
+	secrets := make(map[string]string)
+	for key, field := range workspace.secretFields() {
+		if *field != "" {
+			secrets[key] = *field
+		}
+	}
+	bundle.Secrets, err = encryptSecrets(passphrase, bundleSecretsContext, secrets)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting secrets: %w", err)
+	}
+	return bundle, nil
+}
+
+// importWorkspace creates workspace name from the bundle, keeping credentials
+// in secretStore. An existing workspace is only replaced when force is set.
+func importWorkspace(bundle *workspaceBundle, name, secretStore string, force bool) (*workspaceConfig, error) {
+	if name == "" {
+		name = bundle.Name
+	}
+	if name == "" {
+		return nil, invalidInput("bundle has no name, please specify one using --name")
+	}
+	if err := validateWorkspaceName(name); err != nil {
+		return nil, err
+	}
+	if _, err := getSecretStore(secretStore); err != nil {
+		return nil, invalidInput("%w", err)
+	}
+	previous, err := loadWorkspaceConfig(name)
+	if err == nil && !force {
+		return nil, invalidInput("workspace %s already exists, use --force to overwrite", name)
+	}
+	workspace := bundle.workspace(name)
+	workspace.SecretStore = secretStore
+	if bundle.Secrets != nil {
+		passphrase, err := readPassphrase("HS_BUNDLE_PASSPHRASE", "Bundle passphrase: ")
+		if err != nil {
+			return nil, fmt.Errorf("error reading passphrase: %w", err)
+		}
+		secrets, err := decryptSecrets(passphrase, bundleSecretsContext, bundle.Secrets)
+		if err != nil {
+			return nil, fmt.Errorf("error decrypting secrets: %w", err)
+		}
+		fields := workspace.secretFields()
+		for key, value := range secrets {
+			if field, ok := fields[key]; ok {
+				*field = value
+			}
+		}
+	}
+	if err := workspace.save(); err != nil {
+		return nil, fmt.Errorf("failed to save workspace: %w", err)
+	}
+	// Credentials of the replaced workspace in another store are stale now
+	if previous != nil && previous.SecretStore != workspace.SecretStore {
+		if store, err := getSecretStore(previous.SecretStore); err == nil && store != nil {
+			if err := store.Delete(name, secretKeys()); err != nil {
+				return nil, fmt.Errorf("removing secrets of the replaced workspace: %w", err)
+			}
+		}
+	}
+	return workspace, nil
+}
+
+// encode serializes the bundle as JSON or YAML. YAML goes through JSON
+// first so both formats share the same field names.
+func (b *workspaceBundle) encode(format string) ([]byte, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "json":
+		return append(data, '\n'), nil
+	case "yaml", "yml":
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(generic)
+	}
+	return nil, fmt.Errorf("unsupported format '%s', use yaml or json", format)
+}
+
+// decodeWorkspaceBundle parses a JSON or YAML bundle
+func decodeWorkspaceBundle(data []byte) (*workspaceBundle, error) {
+	var generic interface{}
+	if err := yaml.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	jsonData, err := json.Marshal(generic)
+	if err != nil {
+		return nil, err
+	}
+	var bundle workspaceBundle
+	if err := json.Unmarshal(jsonData, &bundle); err != nil {
+		return nil, err
+	}
+	if bundle.Kind != workspaceBundleKind {
+		return nil, fmt.Errorf("not a workspace bundle (kind: '%s')", bundle.Kind)
+	}
+	if bundle.Version > 1 {
+		return nil, fmt.Errorf("unsupported bundle version %d", bundle.Version)
+	}
+	return &bundle, nil
+}
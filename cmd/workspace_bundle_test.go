@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func exportImport(t *testing.T, includeSecrets bool, name string, force bool) (*workspaceConfig, error) {
+	t.Helper()
+	bundle, err := exportWorkspace("dev", includeSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := bundle.encode("yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := decodeWorkspaceBundle(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return importWorkspace(decoded, name, secretStorePlain, force)
+}
+
+func TestWorkspaceBundleRoundTrip(t *testing.T) {
+	testWorkspaceRoot(t)
+	t.Setenv("HS_BUNDLE_PASSPHRASE", "passphrase")
+	saveTestWorkspace(t, &workspaceConfig{Name: "dev", DefaultRegion: "eu-west", IAMSelectedOrgName: "org",
+		IAMAccessToken: "access", IAMRefreshToken: "refresh", IAMAccessTokenExpires: 100})
+
+	imported, err := exportImport(t, false, "plain", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	onDisk := loadTestWorkspace(t, imported.Name)
+	if onDisk.DefaultRegion != "eu-west" || onDisk.IAMSelectedOrgName != "org" {
+		t.Errorf("settings were not imported: %+v", onDisk)
+	}
+	if onDisk.IAMAccessToken != "" || onDisk.IAMRefreshToken != "" {
+		t.Errorf("expected no credentials without secrets: %+v", onDisk)
+	}
+
+	if _, err := exportImport(t, true, "secrets", false); err != nil {
+		t.Fatal(err)
+	}
+	onDisk = loadTestWorkspace(t, "secrets")
+	if onDisk.IAMAccessToken != "access" || onDisk.IAMRefreshToken != "refresh" {
+		t.Errorf("expected credentials to be imported: %+v", onDisk)
+	}
+}
+
+func TestWorkspaceBundleWrongPassphrase(t *testing.T) {
+	testWorkspaceRoot(t)
+	saveTestWorkspace(t, &workspaceConfig{Name: "dev", IAMAccessToken: "access"})
+	t.Setenv("HS_BUNDLE_PASSPHRASE", "passphrase")
+	bundle, err := exportWorkspace("dev", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HS_BUNDLE_PASSPHRASE", "wrong")
+	if _, err := importWorkspace(bundle, "copy", secretStorePlain, false); err == nil {
+		t.Fatal("expected error for a wrong passphrase")
+	}
+	if workspaceExists("copy") {
+		t.Error("expected no workspace after a failed import")
+	}
+}
+
+func TestWorkspaceBundleNameCollision(t *testing.T) {
+	testWorkspaceRoot(t)
+	saveTestWorkspace(t, &workspaceConfig{Name: "dev", DefaultRegion: "eu-west"})
+	saveTestWorkspace(t, &workspaceConfig{Name: "prod", DefaultRegion: "us-east",
+		IAMAccessToken: "old", IAMAccessTokenExpires: 200})
+
+	if _, err := exportImport(t, false, "prod", false); err == nil {
+		t.Fatal("expected error when the workspace exists")
+	}
+	if _, err := exportImport(t, false, "prod", true); err != nil {
+		t.Fatal(err)
+	}
+	onDisk := loadTestWorkspace(t, "prod")
+	if onDisk.DefaultRegion != "eu-west" || onDisk.IAMAccessToken != "" || onDisk.IAMAccessTokenExpires != 0 {
+		t.Errorf("expected the workspace to be replaced including its tokens: %+v", onDisk)
+	}
+}
+
+func TestWorkspaceBundleRejectsUnsafeNames(t *testing.T) {
+	testWorkspaceRoot(t)
+	for _, name := range []string{"../../.ssh/x", "..", "a/b", `a\b`, "."} {
+		bundle := &workspaceBundle{Kind: workspaceBundleKind, Version: 1, Name: name}
+		if _, err := importWorkspace(bundle, "", secretStorePlain, true); err == nil {
+			t.Errorf("%q: expected error for an unsafe bundle name", name)
+		}
+		if _, err := importWorkspace(&workspaceBundle{Name: "ok"}, name, secretStorePlain, true); err == nil {
+			t.Errorf("%q: expected error for an unsafe --name", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(workspaceRoot(), "..", "..", ".ssh")); !os.IsNotExist(err) {
+		t.Error("expected nothing to be written outside the workspace root")
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// workspaceCloneCmd represents the clone command
+var workspaceCloneCmd = &cobra.Command{
+	Use:     "clone <source> <destination>",
+	Aliases: []string{"cp"},
+	Short:   "Clone a workspace",
+	Long: `Creates a new workspace as a copy of an existing one.
+
+Use --no-tokens to leave out all credentials, e.g. when creating a workspace
+for a different account with the same regional settings.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return cmd.Help()
+		}
+		noTokens, _ := cmd.Flags().GetBool("no-tokens")
+		if err := cloneWorkspace(args[0], args[1], noTokens); err != nil {
+			return fmt.Errorf("failed to clone workspace %s: %w", args[0], err)
+		}
+		fmt.Printf("workspace %s cloned to %s\n", args[0], args[1])
+		return nil
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceCloneCmd)
+	workspaceCloneCmd.Flags().Bool("no-tokens", false, "Do not copy credentials")
+}
@@ -33,17 +33,16 @@ var workspaceDeleteCmd = &cobra.Command{
 	Aliases: []string{"d", "del"},
 	Short:   "Delete a workspace",
 	Long:    `Deletes a workspace.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			_ = cmd.Help()
-			return
+			return cmd.Help()
 		}
 		workspace := args[0]
 		if err := currentWorkspace.delete(workspace); err != nil {
-			fmt.Printf("cannot delete workspace %s: %v\n", workspace, err)
-			return
+			return fmt.Errorf("cannot delete workspace %s: %w", workspace, err)
 		}
 		fmt.Printf("workspace %s deleted\n", workspace)
+		return nil
 	},
 }
 
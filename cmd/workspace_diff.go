@@ -0,0 +1,124 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cheynewallace/tabby"
+	"github.com/spf13/cobra"
+)
+
+type workspaceSetting struct {
+	Setting string `json:"setting"`
+	A       string `json:"a"`
+	B       string `json:"b"`
+}
+
+// workspaceSettings returns the comparable, non-secret settings of a workspace
+func workspaceSettings(w *workspaceConfig) [][2]string {
+	clusters := make([]string, 0)
+	for _, c := range w.IronConfig.ClusterInfo {
+		clusters = append(clusters, c.ClusterID)
+	}
+	secretStore := w.SecretStore
+	if secretStore == "" {
+		secretStore = secretStorePlain
+	}
+	return [][2]string{
+		{"default region", w.DefaultRegion},
+		{"default environment", w.DefaultEnvironment},
+		{"IAM region", w.IAMRegion},
+		{"IAM environment", w.IAMEnvironment},
+		{"IAM organization", w.IAMSelectedOrg},
+		{"IAM organization name", w.IAMSelectedOrgName},
+		{"PKI logical path", w.PKILogicalPath},
+		{"S3Creds product key", w.S3CredsProductKey},
+		{"Iron project", w.IronConfig.ProjectID},
+		{"Iron email", w.IronConfig.Email},
+		{"Iron clusters", strings.Join(clusters, ",")},
+		{"TFState URL", w.TFStateInstanceURL},
+		{"secret store", secretStore},
+	}
+}
+
+// workspaceDiffCmd represents the diff command
+var workspaceDiffCmd = &cobra.Command{
+	Use:   "diff <workspace> <workspace>",
+	Short: "Show differences between two workspaces",
+	Long:  `Shows which regional, organization, PKI, Iron and TFState settings differ between two workspaces.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return cmd.Help()
+		}
+		a, err := loadWorkspaceConfig(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to load workspace %s: %w", args[0], err)
+		}
+		b, err := loadWorkspaceConfig(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to load workspace %s: %w", args[1], err)
+		}
+		all, _ := cmd.Flags().GetBool("all")
+		settingsA := workspaceSettings(a)
+		settingsB := workspaceSettings(b)
+		diff := make([]workspaceSetting, 0)
+		for i := range settingsA {
+			if !all && settingsA[i][1] == settingsB[i][1] {
+				continue
+			}
+			diff = append(diff, workspaceSetting{
+				Setting: settingsA[i][0],
+				A:       settingsA[i][1],
+				B:       settingsB[i][1],
+			})
+		}
+		if !tableOutput() {
+			rows := make([][]interface{}, 0)
+			for _, d := range diff {
+				rows = append(rows, []interface{}{d.Setting, d.A, d.B})
+			}
+			return printOutput(diff, []string{"setting", args[0], args[1]}, rows)
+		}
+		if len(diff) == 0 {
+			fmt.Printf("workspaces %s and %s have identical settings\n", args[0], args[1])
+			return nil
+		}
+		t := tabby.New()
+		t.AddHeader("setting", args[0], args[1])
+		for _, d := range diff {
+			marker := ""
+			if all && d.A != d.B {
+				marker = " *"
+			}
+			t.AddLine(d.Setting+marker, d.A, d.B)
+		}
+		t.Print()
+		return nil
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceDiffCmd)
+	workspaceDiffCmd.Flags().Bool("all", false, "Show all settings, not only the differences")
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// workspaceExportCmd represents the export command
+var workspaceExportCmd = &cobra.Command{
+	Use:   "export [workspace]",
+	Short: "Export a workspace as a portable bundle",
+	Long: `Exports the settings of a workspace as a YAML or JSON bundle which can
+be imported by others using 'hs workspace import'.
+
+Credentials are left out unless --include-secrets is given, in which case
+they are encrypted with a passphrase read from HS_BUNDLE_PASSPHRASE or
+prompted for.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := currentWorkspace.Name
+		if len(args) > 0 {
+			name = args[0]
+		}
+		format, _ := cmd.Flags().GetString("format")
+		file, _ := cmd.Flags().GetString("file")
+		includeSecrets, _ := cmd.Flags().GetBool("include-secrets")
+
+		bundle, err := exportWorkspace(name, includeSecrets)
+		if err != nil {
+			return err
+		}
+		data, err := bundle.encode(format)
+		if err != nil {
+			return fmt.Errorf("error encoding bundle: %w", err)
+		}
+		if file == "" {
+			fmt.Printf("%s", data)
+			return nil
+		}
+		if err := os.WriteFile(file, data, 0600); err != nil {
+			return fmt.Errorf("error writing bundle: %w", err)
+		}
+		fmt.Printf("workspace %s exported to %s\n", name, file)
+		return nil
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceExportCmd)
+	workspaceExportCmd.Flags().StringP("file", "f", "", "Write the bundle to this file instead of stdout")
+	workspaceExportCmd.Flags().String("format", "yaml", "Bundle format: yaml or json")
+	workspaceExportCmd.Flags().Bool("include-secrets", false, "Include passphrase encrypted credentials")
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// workspaceImportCmd represents the import command
+var workspaceImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import a workspace bundle",
+	Long: `Creates a workspace from a bundle made with 'hs workspace export'.
+
+Encrypted credentials in the bundle are decrypted with the passphrase
+read from HS_BUNDLE_PASSPHRASE or prompted for.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return cmd.Help()
+		}
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("error reading bundle: %w", err)
+		}
+		bundle, err := decodeWorkspaceBundle(data)
+		if err != nil {
+			return invalidInput("error decoding bundle: %w", err)
+		}
+		name, _ := cmd.Flags().GetString("name")
+		force, _ := cmd.Flags().GetBool("force")
+		secretStore, _ := cmd.Flags().GetString("secret-store")
+		workspace, err := importWorkspace(bundle, name, secretStore, force)
+		if err != nil {
+			return err
+		}
+		name = workspace.Name
+		fmt.Printf("workspace %s imported\n", name)
+		if use, _ := cmd.Flags().GetBool("use"); use {
+			if err := workspace.setDefault(name); err != nil {
+				return fmt.Errorf("failed to select workspace %s: %w", name, err)
+			}
+			fmt.Printf("selected workspace %s\n", name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceImportCmd)
+	workspaceImportCmd.Flags().String("name", "", "Import under this name instead of the bundle name")
+	workspaceImportCmd.Flags().Bool("force", false, "Overwrite an existing workspace")
+	workspaceImportCmd.Flags().Bool("use", false, "Select the workspace after importing")
+	workspaceImportCmd.Flags().String("secret-store", secretStorePlain, "Where to keep credentials: plain, keyring or file")
+}
@@ -23,57 +23,354 @@ package cmd
 
 import (
 	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/dip-software/go-dip-api/iam"
+	"github.com/dip-software/go-dip-api/iron"
 	"github.com/spf13/cobra"
 )
 
+// credentialHealth describes the state of one credential held by a workspace
+type credentialHealth struct {
+	Service          string     `json:"service"`
+	Status           string     `json:"status"`
+	Detail           string     `json:"detail,omitempty"`
+	ExpiresAt        *time.Time `json:"expiresAt,omitempty"`
+	RefreshExpiresAt *time.Time `json:"refreshExpiresAt,omitempty"`
+	Healthy          bool       `json:"healthy"`
+}
+
+type workspaceInfo struct {
+	Name                 string             `json:"name"`
+	DefaultRegion        string             `json:"defaultRegion"`
+	DefaultEnvironment   string             `json:"defaultEnvironment"`
+	SecretStore          string             `json:"secretStore"`
+	IAMRegion            string             `json:"iamRegion"`
+	IAMEnvironment       string             `json:"iamEnvironment"`
+	IAMSelectedOrg       string             `json:"iamSelectedOrg"`
+	IAMSelectedOrgName   string             `json:"iamSelectedOrgName"`
+	Credentials          []credentialHealth `json:"credentials"`
+	CredentialsRequiring int                `json:"credentialsRequiringAction"`
+}
+
 // workspaceInfoCmd represents the info command
 var workspaceInfoCmd = &cobra.Command{
 	Use:     "info",
 	Aliases: []string{"i"},
 	Short:   "Information on current workspace",
-	Long:    `Shows detailed information on current workspace.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Workspace name:            %s\n", currentWorkspace.Name)
-		fmt.Printf("Default region:            %s\n", currentWorkspace.DefaultRegion)
-		fmt.Printf("Default environment:       %s\n", currentWorkspace.DefaultEnvironment)
-		loginStatus := "never logged in"
-		if expired := currentWorkspace.iamExpireTime(); expired != nil {
-			if currentWorkspace.iamLoginExpired() {
-				loginStatus = fmt.Sprintf("refresh required (expired at %v)", expired)
-				if currentWorkspace.IAMRefreshToken == "" {
-					loginStatus = "login required"
-				}
-			} else {
-				loginStatus = fmt.Sprintf("active (expires at %v)", expired)
-			}
+	Long: `Shows detailed information on current workspace and the health of
+every credential it holds.
+
+With --check each configured service is probed and the command exits
+with a non-zero status when a credential needs attention, which makes
+it usable in shell prompts and pre-flight scripts. IAM tokens are only
+introspected, never refreshed, and the remaining lifetime of the refresh
+token is reported when IAM provides it.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		check, _ := cmd.Flags().GetBool("check")
+		info := workspaceInfo{
+			Name:               currentWorkspace.Name,
+			DefaultRegion:      currentWorkspace.DefaultRegion,
+			DefaultEnvironment: currentWorkspace.DefaultEnvironment,
+			SecretStore:        currentWorkspace.SecretStore,
+			IAMRegion:          currentWorkspace.IAMRegion,
+			IAMEnvironment:     currentWorkspace.IAMEnvironment,
+			IAMSelectedOrg:     currentWorkspace.IAMSelectedOrg,
+			IAMSelectedOrgName: currentWorkspace.IAMSelectedOrgName,
+		}
+		if info.SecretStore == "" {
+			info.SecretStore = secretStorePlain
+		}
+		info.Credentials = []credentialHealth{
+			iamHealth(check),
+			uaaHealth(),
+			tfstateHealth(check),
+			ironHealth(check),
 		}
-		fmt.Printf("\nIAM Login status:          %s\n", loginStatus)
-		fmt.Printf("IAM Region:                %s\n", currentWorkspace.IAMRegion)
-		fmt.Printf("IAM Environment:           %s\n", currentWorkspace.IAMEnvironment)
-		fmt.Printf("IAM Selected Organization: %s (%s)\n",
-			currentWorkspace.IAMSelectedOrg,
-			currentWorkspace.IAMSelectedOrgName)
-		if currentWorkspace.UAAIDToken != "" {
-			loginStatus := "never logged in"
-			if expired := currentWorkspace.uaaExpireTime(); expired != nil {
-				if currentWorkspace.uaaLoginExpired() {
-					loginStatus = fmt.Sprintf("refresh required (expired at %v)", expired)
-					if currentWorkspace.UAARefreshToken == "" {
-						loginStatus = "login required"
-					}
-				} else {
-					loginStatus = fmt.Sprintf("active (expires at %v)", expired)
-				}
+		for _, c := range info.Credentials {
+			if !c.Healthy {
+				info.CredentialsRequiring++
 			}
-			fmt.Printf("\nUAA Login status:          %s\n", loginStatus)
-			fmt.Printf("UAA Region:                %s\n", currentWorkspace.IAMRegion)
-			fmt.Printf("UAA Environment:           %s\n", currentWorkspace.IAMEnvironment)
 		}
-		fmt.Printf("\n")
+		if tableOutput() {
+			printWorkspaceInfo(info)
+		} else if err := printObject(info); err != nil {
+			return err
+		}
+		if check && info.CredentialsRequiring > 0 {
+			return authRequired("%d credential(s) require attention", info.CredentialsRequiring)
+		}
+		return nil
 	},
 }
 
+func printWorkspaceInfo(info workspaceInfo) {
+	fmt.Printf("Workspace name:            %s\n", info.Name)
+	fmt.Printf("Default region:            %s\n", info.DefaultRegion)
+	fmt.Printf("Default environment:       %s\n", info.DefaultEnvironment)
+	fmt.Printf("Secret store:              %s\n", info.SecretStore)
+	fmt.Printf("\nIAM Region:                %s\n", info.IAMRegion)
+	fmt.Printf("IAM Environment:           %s\n", info.IAMEnvironment)
+	fmt.Printf("IAM Selected Organization: %s (%s)\n", info.IAMSelectedOrg, info.IAMSelectedOrgName)
+	fmt.Printf("\n")
+	for _, c := range info.Credentials {
+		marker := "✓"
+		if !c.Healthy {
+			marker = "✗"
+		}
+		line := c.Status
+		if c.Detail != "" {
+			line += " (" + c.Detail + ")"
+		}
+		fmt.Printf("%s %-24s%s\n", marker, c.Service+":", line)
+	}
+	fmt.Printf("\n")
+}
+
+// expiryDetail describes when a token expires relative to now
+func expiryDetail(expires time.Time) string {
+	remaining := time.Until(expires)
+	if remaining < 0 {
+		return fmt.Sprintf("expired %s ago at %v", shortDuration(-remaining), expires)
+	}
+	return fmt.Sprintf("expires in %s at %v", shortDuration(remaining), expires)
+}
+
+func iamHealth(check bool) credentialHealth {
+	return checkIAMHealth(currentWorkspace, check, newIAMClient)
+}
+
+// checkIAMHealth reports the IAM session of w. The probe only introspects the
+// tokens and never refreshes them, as a refresh on every shell prompt would
+// rotate and rewrite the workspace tokens all the time.
+func checkIAMHealth(w *workspaceConfig, check bool, newClient func() (*iam.Client, error)) credentialHealth {
+	health := credentialHealth{Service: "IAM login"}
+	expires := w.iamExpireTime()
+	serviceKey := w.IAMServiceKey != ""
+	if (expires == nil || w.IAMAccessToken == "") && !serviceKey {
+		health.Status = "never logged in"
+		return health
+	}
+	expired := w.iamLoginExpired()
+	switch {
+	case expired && serviceKey:
+		health.Status = "login on next use"
+		health.Healthy = true
+	case expired && w.IAMRefreshToken == "":
+		health.Status = "login required"
+	case expired:
+		health.Status = "refresh required"
+		health.Healthy = true
+	default:
+		health.Status = "active"
+		health.Healthy = true
+	}
+	var accessDetail, renewDetail string
+	if expires != nil && w.IAMAccessToken != "" {
+		health.ExpiresAt = expires
+		accessDetail = expiryDetail(*expires)
+	}
+	switch {
+	case serviceKey:
+		renewDetail = "service identity logs in again automatically"
+	case w.IAMRefreshToken != "":
+		renewDetail = "refresh token present"
+	}
+	if check && health.Healthy {
+		probe, err := probeIAMHealth(w, newClient)
+		switch {
+		case err != nil:
+			health.Status = "login required"
+			health.Healthy = false
+			accessDetail, renewDetail = err.Error(), ""
+		case probe.revoked:
+			health.Status = "refresh required"
+			accessDetail = "access token was revoked"
+		case !expired:
+			accessDetail += ", verified"
+		}
+		switch {
+		case err != nil:
+		case probe.refreshExpires != nil:
+			health.RefreshExpiresAt = probe.refreshExpires
+			renewDetail = "refresh token " + expiryDetail(*probe.refreshExpires)
+		case probe.refreshUnknown:
+			renewDetail = "refresh token lifetime unknown"
+		}
+	}
+	details := make([]string, 0)
+	for _, detail := range []string{accessDetail, renewDetail} {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+	health.Detail = strings.Join(details, ", ")
+	return health
+}
+
+// iamProbe is the result of introspecting the tokens of a workspace
+type iamProbe struct {
+	revoked        bool
+	refreshExpires *time.Time
+	refreshUnknown bool
+}
+
+// probeIAMHealth introspects the tokens of w, returning an error when a login is required
+func probeIAMHealth(w *workspaceConfig, newClient func() (*iam.Client, error)) (*iamProbe, error) {
+	probe := &iamProbe{}
+	serviceKey := w.IAMServiceKey != ""
+	if serviceKey {
+		key, err := decodeKey([]byte(w.IAMServiceKey))
+		if err == nil {
+			_, err = key.privateKey()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stored service key: %w", err)
+		}
+	}
+	iamClient, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	if !w.iamLoginExpired() {
+		introspect, _, err := iamClient.Introspect()
+		if err != nil {
+			return nil, fmt.Errorf("unable to verify token: %w", err)
+		}
+		if !introspect.Active && !serviceKey && w.IAMRefreshToken == "" {
+			return nil, fmt.Errorf("access token was revoked")
+		}
+		probe.revoked = !introspect.Active
+	}
+	if w.IAMRefreshToken != "" && !serviceKey {
+		introspect, _, err := iamClient.WithToken(w.IAMRefreshToken).Introspect()
+		switch {
+		case err != nil:
+			probe.refreshUnknown = true
+		case !introspect.Active:
+			return nil, fmt.Errorf("refresh token expired or was revoked")
+		case introspect.Expires == 0:
+			probe.refreshUnknown = true
+		default:
+			refreshExpires := time.Unix(introspect.Expires, 0)
+			probe.refreshExpires = &refreshExpires
+		}
+	}
+	return probe, nil
+}
+
+func uaaHealth() credentialHealth {
+	health := credentialHealth{Service: "UAA login", Healthy: true}
+	if currentWorkspace.UAAIDToken == "" && currentWorkspace.UAAToken == "" {
+		health.Status = "not configured"
+		return health
+	}
+	expires := currentWorkspace.uaaExpireTime()
+	switch {
+	case expires == nil:
+		health.Status = "login required"
+		health.Healthy = false
+		return health
+	case currentWorkspace.uaaLoginExpired() && currentWorkspace.UAARefreshToken == "":
+		health.Status = "login required"
+		health.Healthy = false
+	case currentWorkspace.uaaLoginExpired():
+		health.Status = "refresh required"
+	default:
+		health.Status = "active"
+	}
+	health.ExpiresAt = expires
+	health.Detail = expiryDetail(*expires)
+	return health
+}
+
+func tfstateHealth(check bool) credentialHealth {
+	health := credentialHealth{Service: "TFState", Healthy: true}
+	if currentWorkspace.TFStateInstanceURL == "" {
+		health.Status = "not configured"
+		return health
+	}
+	health.Detail = currentWorkspace.TFStateInstanceURL
+	if currentWorkspace.TFStateCreds == "" {
+		health.Status = "credentials missing"
+		health.Healthy = false
+		return health
+	}
+	health.Status = "credentials stored"
+	if !check {
+		return health
+	}
+	httpClient := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+		},
+	}
+	req, err := http.NewRequest(http.MethodGet, currentWorkspace.TFStateInstanceURL+"/states", nil)
+	if err != nil {
+		health.Status = "invalid URL"
+		health.Detail = err.Error()
+		health.Healthy = false
+		return health
+	}
+	req.Header.Set("Authorization", "Basic "+currentWorkspace.TFStateCreds)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		health.Status = "unreachable"
+		health.Detail = err.Error()
+		health.Healthy = false
+		return health
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		health.Status = "authentication failed"
+		health.Healthy = false
+	case resp.StatusCode >= 300:
+		health.Status = fmt.Sprintf("error (HTTP %d)", resp.StatusCode)
+		health.Healthy = false
+	default:
+		health.Status = "authenticated"
+	}
+	return health
+}
+
+func ironHealth(check bool) credentialHealth {
+	health := credentialHealth{Service: "Iron config", Healthy: true}
+	config, err := readIronConfig()
+	if os.IsNotExist(err) {
+		health.Status = "not configured"
+		return health
+	}
+	if err != nil {
+		health.Status = "unreadable"
+		health.Detail = err.Error()
+		health.Healthy = false
+		return health
+	}
+	health.Status = "readable"
+	health.Detail = "project " + config.ProjectID
+	if !check {
+		return health
+	}
+	client, err := iron.NewClient(config)
+	if err == nil {
+		_, _, err = client.Codes.GetCodes()
+	}
+	if err != nil {
+		health.Status = "authentication failed"
+		health.Detail = err.Error()
+		health.Healthy = false
+		return health
+	}
+	health.Status = "authenticated"
+	return health
+}
+
 func init() {
 	workspaceCmd.AddCommand(workspaceInfoCmd)
+	workspaceInfoCmd.Flags().Bool("check", false, "Probe each service and exit non-zero when a credential needs attention")
 }
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dip-software/go-dip-api/iam"
+)
+
+func TestCheckIAMHealth(t *testing.T) {
+	refreshExpires := time.Now().Add(24 * time.Hour).Unix()
+	var tokenRequests int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize/oauth2/introspect", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		switch r.Form.Get("token") {
+		case "access":
+			_ = json.NewEncoder(w).Encode(iam.IntrospectResponse{Active: true, Expires: time.Now().Add(time.Hour).Unix()})
+		case "refresh":
+			_ = json.NewEncoder(w).Encode(iam.IntrospectResponse{Active: true, Expires: refreshExpires})
+		default:
+			_ = json.NewEncoder(w).Encode(iam.IntrospectResponse{Active: false})
+		}
+	})
+	mux.HandleFunc("/authorize/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	key, err := newTestServiceKey().encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Hour).Unix()
+	for _, tc := range []struct {
+		name      string
+		workspace *workspaceConfig
+		check     bool
+		status    string
+		healthy   bool
+		detail    string
+	}{
+		{"never logged in", &workspaceConfig{}, true, "never logged in", false, ""},
+		{"active", &workspaceConfig{IAMAccessToken: "access", IAMAccessTokenExpires: future}, false, "active", true, "expires in"},
+		{"active verified", &workspaceConfig{IAMAccessToken: "access", IAMRefreshToken: "refresh", IAMAccessTokenExpires: future}, true, "active", true, "verified, refresh token expires in"},
+		{"revoked", &workspaceConfig{IAMAccessToken: "revoked", IAMAccessTokenExpires: future}, true, "login required", false, "revoked"},
+		{"revoked with refresh", &workspaceConfig{IAMAccessToken: "revoked", IAMRefreshToken: "refresh", IAMAccessTokenExpires: future}, true, "refresh required", true, "access token was revoked"},
+		{"expired", &workspaceConfig{IAMAccessToken: "access", IAMAccessTokenExpires: past}, true, "login required", false, "expired"},
+		{"expired with refresh", &workspaceConfig{IAMAccessToken: "access", IAMRefreshToken: "refresh", IAMAccessTokenExpires: past}, true, "refresh required", true, "refresh token expires in"},
+		{"refresh revoked", &workspaceConfig{IAMAccessToken: "access", IAMRefreshToken: "dead", IAMAccessTokenExpires: past}, true, "login required", false, "refresh token expired or was revoked"},
+		{"service key", &workspaceConfig{IAMAccessToken: "access", IAMServiceKey: key, IAMAccessTokenExpires: past}, true, "login on next use", true, "logs in again automatically"},
+		{"service key without token", &workspaceConfig{IAMServiceKey: key}, false, "login on next use", true, "logs in again automatically"},
+		{"broken service key", &workspaceConfig{IAMAccessToken: "access", IAMServiceKey: "broken", IAMAccessTokenExpires: past}, true, "login required", false, "stored service key"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := tc.workspace
+			before, refresh := w.iamToken(), w.IAMRefreshToken
+			health := checkIAMHealth(w, tc.check, func() (*iam.Client, error) {
+				client, err := iam.NewClient(http.DefaultClient, &iam.Config{
+					OAuth2ClientID: "client",
+					OAuth2Secret:   "secret",
+					IAMURL:         server.URL,
+					IDMURL:         server.URL,
+				})
+				if err != nil {
+					return nil, err
+				}
+				client.SetTokens(w.IAMAccessToken, w.IAMRefreshToken, "", w.IAMAccessTokenExpires)
+				return client, nil
+			})
+			if health.Status != tc.status || health.Healthy != tc.healthy || !strings.Contains(health.Detail, tc.detail) {
+				t.Errorf("expected %s healthy=%v %q, got %s healthy=%v %q",
+					tc.status, tc.healthy, tc.detail, health.Status, health.Healthy, health.Detail)
+			}
+			if w.iamToken() != before || w.IAMRefreshToken != refresh {
+				t.Error("expected the workspace to be left untouched")
+			}
+		})
+	}
+	if tokenRequests > 0 {
+		t.Errorf("expected no token requests, got %d", tokenRequests)
+	}
+}
+
+func TestTFStateHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/states" || r.Header.Get("Authorization") != "Basic good" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+	defer func() { currentWorkspace = nil }()
+
+	for _, tc := range []struct {
+		url, creds, status string
+		healthy            bool
+	}{
+		{server.URL, "good", "authenticated", true},
+		{server.URL, "bad", "authentication failed", false},
+		{"http://[::1", "good", "invalid URL", false},
+	} {
+		currentWorkspace = &workspaceConfig{TFStateInstanceURL: tc.url, TFStateCreds: tc.creds}
+		health := tfstateHealth(true)
+		if health.Status != tc.status || health.Healthy != tc.healthy {
+			t.Errorf("%s %s: expected %s, got %s (%s)", tc.url, tc.creds, tc.status, health.Status, health.Detail)
+		}
+	}
+}
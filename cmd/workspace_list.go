@@ -23,9 +23,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-
-	"github.com/cheynewallace/tabby"
 
 	"github.com/spf13/cobra"
 )
@@ -36,24 +33,30 @@ var workspaceListCmd = &cobra.Command{
 	Aliases: []string{"l", "ls"},
 	Short:   "List available workspaces",
 	Long:    `Lists available workspaces.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		list, current, err := currentWorkspace.list()
 		if err != nil {
-			fmt.Printf("failed to list workspaces: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("failed to list workspaces: %w", err)
+		}
+		type workspaceEntry struct {
+			Name        string `json:"name"`
+			Region      string `json:"region"`
+			Environment string `json:"environment"`
+			Current     bool   `json:"current"`
 		}
-		t := tabby.New()
-		t.AddHeader("workspace", "region", "environment")
+		entries := make([]workspaceEntry, 0)
+		rows := make([][]interface{}, 0)
 		for _, w := range list {
 			if config, err := loadWorkspaceConfig(w); err == nil {
 				name := "  " + config.Name
 				if w == current {
 					name = "✓ " + config.Name
 				}
-				t.AddLine(name, config.DefaultRegion, config.DefaultEnvironment)
+				entries = append(entries, workspaceEntry{config.Name, config.DefaultRegion, config.DefaultEnvironment, w == current})
+				rows = append(rows, []interface{}{name, config.DefaultRegion, config.DefaultEnvironment})
 			}
 		}
-		t.Print()
+		return printOutput(entries, []string{"workspace", "region", "environment"}, rows)
 	},
 }
 
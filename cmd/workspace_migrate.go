@@ -0,0 +1,90 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// workspaceVersion is the current workspace config schema version
+const workspaceVersion = 2
+
+// workspaceMigration upgrades a raw workspace config from version from to from+1
+type workspaceMigration struct {
+	from    int
+	migrate func(raw map[string]interface{}) error
+}
+
+var workspaceMigrations = []workspaceMigration{
+	// Pre-versioning files carry no Version field
+	{from: 0, migrate: func(raw map[string]interface{}) error {
+		return nil
+	}},
+	// Fix the misspelled DefaultEnvironment tag and record the secret store
+	{from: 1, migrate: func(raw map[string]interface{}) error {
+		if env, ok := raw["DefaultEnvironemnt"]; ok {
+			if _, exists := raw["DefaultEnvironment"]; !exists {
+				raw["DefaultEnvironment"] = env
+			}
+			delete(raw, "DefaultEnvironemnt")
+		}
+		if _, ok := raw["SecretStore"]; !ok {
+			raw["SecretStore"] = secretStorePlain
+		}
+		return nil
+	}},
+}
+
+// migrateWorkspace upgrades raw workspace config data to workspaceVersion.
+// It returns the migrated data and the version the data was read at.
+func migrateWorkspace(data []byte) ([]byte, int, error) {
+	raw := make(map[string]interface{})
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, err
+	}
+	version := 0
+	if v, ok := raw["Version"].(float64); ok {
+		version = int(v)
+	}
+	if version > workspaceVersion {
+		return nil, version, fmt.Errorf("workspace version %d is newer than supported version %d, please upgrade hs",
+			version, workspaceVersion)
+	}
+	if version == workspaceVersion {
+		return data, version, nil
+	}
+	for _, m := range workspaceMigrations {
+		if m.from < version {
+			continue
+		}
+		if err := m.migrate(raw); err != nil {
+			return nil, version, fmt.Errorf("migrating workspace from version %d: %w", m.from, err)
+		}
+		raw["Version"] = m.from + 1
+	}
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return nil, version, err
+	}
+	return migrated, version, nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+func TestMigrateWorkspaceGolden(t *testing.T) {
+	for _, version := range []string{"v0", "v1", "v2"} {
+		t.Run(version, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", "workspaces", version+".json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden, err := os.ReadFile(filepath.Join("testdata", "workspaces", version+".golden.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			migrated, _, err := migrateWorkspace(input)
+			if err != nil {
+				t.Fatalf("migrate failed: %v", err)
+			}
+			var got, want interface{}
+			if err := json.Unmarshal(migrated, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(golden, &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("migrated workspace does not match golden file\ngot:  %s", migrated)
+			}
+		})
+	}
+}
+
+func TestMigrateWorkspaceNewerVersion(t *testing.T) {
+	_, _, err := migrateWorkspace([]byte(`{"Version":99}`))
+	if err == nil {
+		t.Error("expected error for unsupported newer version")
+	}
+}
+
+func TestLoadWorkspaceConfigMigratesAndBacksUp(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	homedir.DisableCache = true
+	defer func() { homedir.DisableCache = false }()
+
+	input, err := os.ReadFile(filepath.Join("testdata", "workspaces", "v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(workspaceRoot(), "legacy.config.json")
+	if err := os.WriteFile(configFile, input, 0600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadWorkspaceConfig("legacy")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if config.DefaultEnvironment != "prod" {
+		t.Errorf("expected DefaultEnvironment 'prod', got '%s'", config.DefaultEnvironment)
+	}
+	if config.Version != workspaceVersion {
+		t.Errorf("expected version %d, got %d", workspaceVersion, config.Version)
+	}
+	backup, err := os.ReadFile(configFile + ".v1.bak")
+	if err != nil {
+		t.Fatalf("expected backup file: %v", err)
+	}
+	if string(backup) != string(input) {
+		t.Error("backup does not match original file")
+	}
+}
+
+func TestConcurrentLoadsMigrateOnce(t *testing.T) {
+	testWorkspaceRoot(t)
+	input, err := os.ReadFile(filepath.Join("testdata", "workspaces", "v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(workspaceRoot(), "legacy.config.json")
+	if err := os.WriteFile(configFile, input, 0600); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			config, err := loadWorkspaceConfig("legacy")
+			if err == nil && config.DefaultEnvironment != "prod" {
+				err = fmt.Errorf("unexpected environment %q", config.DefaultEnvironment)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if backup, err := os.ReadFile(configFile + ".v1.bak"); err != nil || string(backup) != string(input) {
+		t.Errorf("expected the original file as backup: %v", err)
+	}
+}
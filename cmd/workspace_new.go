@@ -33,29 +33,35 @@ var workspaceNewCmd = &cobra.Command{
 	Aliases: []string{"n"},
 	Short:   "Create a new workspace",
 	Long:    `Creates a new workspace.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			_ = cmd.Help()
-			return
+			return cmd.Help()
 		}
 		region, _ := cmd.Flags().GetString("region")
 		environment, _ := cmd.Flags().GetString("environment")
+		secretStore, _ := cmd.Flags().GetString("secret-store")
+		if _, err := getSecretStore(secretStore); err != nil {
+			return invalidInput("%w", err)
+		}
 
 		workspace := args[0]
+		if err := validateWorkspaceName(workspace); err != nil {
+			return err
+		}
 
 		newWorkspace := &workspaceConfig{}
 		newWorkspace.DefaultEnvironment = environment
 		newWorkspace.DefaultRegion = region
 		newWorkspace.Name = workspace
+		newWorkspace.SecretStore = secretStore
 		if err := newWorkspace.save(); err != nil {
-			fmt.Printf("failed to create new workspace: %v\n", err)
-			return
+			return fmt.Errorf("failed to create new workspace: %w", err)
 		}
 		if err := newWorkspace.setDefault(workspace); err != nil {
-			fmt.Printf("failed to select workspace %s: %v\n", workspace, err)
-			return
+			return fmt.Errorf("failed to select workspace %s: %w", workspace, err)
 		}
 		fmt.Printf("selected new workspace %s\n", workspace)
+		return nil
 	},
 }
 
@@ -63,4 +69,5 @@ func init() {
 	workspaceCmd.AddCommand(workspaceNewCmd)
 	workspaceNewCmd.Flags().StringP("region", "r", "us-east", "Default region to use")
 	workspaceNewCmd.Flags().StringP("environment", "e", "client-test", "Default environment to use")
+	workspaceNewCmd.Flags().String("secret-store", secretStorePlain, "Where to keep credentials: plain, keyring or file")
 }
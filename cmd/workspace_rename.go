@@ -0,0 +1,63 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// workspaceRenameCmd represents the rename command
+var workspaceRenameCmd = &cobra.Command{
+	Use:     "rename <workspace> <new-name>",
+	Aliases: []string{"mv"},
+	Short:   "Rename a workspace",
+	Long:    `Renames a workspace, keeping it selected if it is the current one.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return cmd.Help()
+		}
+		from, to := args[0], args[1]
+		if from == "default" {
+			return invalidInput("cannot rename default")
+		}
+		wasCurrent := from == currentWorkspaceName()
+		if err := cloneWorkspace(from, to, false); err != nil {
+			return fmt.Errorf("failed to rename workspace %s: %w", from, err)
+		}
+		if wasCurrent {
+			if err := (&workspaceConfig{}).setDefault(to); err != nil {
+				return fmt.Errorf("failed to select workspace %s: %w", to, err)
+			}
+		}
+		if err := removeWorkspace(from); err != nil {
+			return fmt.Errorf("workspace %s copied to %s but removing the original failed: %w", from, to, err)
+		}
+		fmt.Printf("workspace %s renamed to %s\n", from, to)
+		return nil
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceRenameCmd)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cheynewallace/tabby"
+	"github.com/spf13/cobra"
+)
+
+// workspaceSecretsCmd represents the secrets command
+var workspaceSecretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Show where workspace credentials are stored",
+	Long: `Shows where the credentials of the current workspace are stored.
+
+Credentials can be kept in the workspace file (plain), in the OS keyring
+(keyring) or in a passphrase encrypted file (file). The passphrase for the
+file store is read from HS_PASSPHRASE or prompted for.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := currentWorkspace.SecretStore
+		if store == "" {
+			store = secretStorePlain
+		}
+		fmt.Printf("Secret store: %s\n\n", store)
+		t := tabby.New()
+		t.AddHeader("secret", "status")
+		fields := currentWorkspace.secretFields()
+		keys := secretKeys()
+		sort.Strings(keys)
+		for _, key := range keys {
+			status := "not set"
+			if currentWorkspace.secretsLocked {
+				status = "locked"
+			} else if *fields[key] != "" {
+				status = "set"
+			}
+			t.AddLine(key, status)
+		}
+		t.Print()
+		return nil
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceSecretsCmd)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2026 Andy Lo-A-Foe <andy.lo-a-foe@philips.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// workspaceSecretsMigrateCmd represents the migrate command
+var workspaceSecretsMigrateCmd = &cobra.Command{
+	Use:   "migrate <plain|keyring|file>",
+	Short: "Move workspace credentials to a different secret store",
+	Long: `Moves the credentials of the current workspace to a different secret store.
+
+Use this to move tokens out of existing plain workspace files.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return cmd.Help()
+		}
+		target := args[0]
+		newStore, err := getSecretStore(target)
+		if err != nil {
+			return invalidInput("%w", err)
+		}
+		if currentWorkspace.secretsLocked {
+			return fmt.Errorf("secrets of workspace %s are locked, cannot migrate", currentWorkspace.Name)
+		}
+		oldStore, err := getSecretStore(currentWorkspace.SecretStore)
+		if err != nil {
+			return invalidInput("%w", err)
+		}
+		previous := currentWorkspace.SecretStore
+		if previous == target || (previous == "" && newStore == nil) {
+			fmt.Printf("workspace %s already uses the %s secret store\n", currentWorkspace.Name, target)
+			return nil
+		}
+		currentWorkspace.SecretStore = target
+		if err := currentWorkspace.save(); err != nil {
+			currentWorkspace.SecretStore = previous
+			return fmt.Errorf("failed to migrate secrets: %w", err)
+		}
+		if oldStore != nil {
+			if err := oldStore.Delete(currentWorkspace.Name, secretKeys()); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to remove secrets from %s store: %v\n", oldStore.Name(), err)
+			}
+		}
+		fmt.Printf("secrets of workspace %s moved to the %s store\n", currentWorkspace.Name, target)
+		return nil
+	},
+}
+
+func init() {
+	workspaceSecretsCmd.AddCommand(workspaceSecretsMigrateCmd)
+}
@@ -23,6 +23,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 )
@@ -33,25 +34,25 @@ var workspaceSetCmd = &cobra.Command{
 	Aliases: []string{"s", "select"},
 	Short:   "Sets a different workspace",
 	Long:    `Sets a different workspace.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			_ = cmd.Help()
-			return
+			return cmd.Help()
 		}
 		workspace := args[0]
 		if err := currentWorkspace.setDefault(workspace); err != nil {
-			fmt.Printf("failed to select workspace %s: %v\n", workspace, err)
-			return
+			return fmt.Errorf("failed to select workspace %s: %w", workspace, err)
 		}
 		fmt.Printf("switched to workspace %s\n", workspace)
 		fmt.Printf("\n")
 		var err error
 		currentWorkspace, err = loadWorkspaceConfig(workspace)
 		if err != nil {
-			fmt.Printf("failed to load workspace: %v\n", err)
-			return
+			return fmt.Errorf("failed to load workspace: %w", err)
 		}
-		workspaceInfoCmd.Run(cmd, args)
+		if err := currentWorkspace.loadSecrets(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: unable to load workspace secrets: %v\n", err)
+		}
+		return workspaceInfoCmd.RunE(cmd, args)
 	},
 }
 
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+func testWorkspaceRoot(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	homedir.DisableCache = true
+	t.Cleanup(func() { homedir.DisableCache = false })
+}
+
+func saveTestWorkspace(t *testing.T, w *workspaceConfig) {
+	t.Helper()
+	if err := w.save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadTestWorkspace(t *testing.T, name string) *workspaceConfig {
+	t.Helper()
+	w, err := loadWorkspaceConfig(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestSaveMergesNewerTokensIntoStaleCopy(t *testing.T) {
+	testWorkspaceRoot(t)
+	saveTestWorkspace(t, &workspaceConfig{Name: "dev", IAMAccessToken: "old", IAMAccessTokenExpires: 100})
+
+	refresher := loadTestWorkspace(t, "dev")
+	stale := loadTestWorkspace(t, "dev")
+	refresher.IAMAccessToken, refresher.IAMRefreshToken, refresher.IAMAccessTokenExpires = "new", "refresh", 200
+	saveTestWorkspace(t, refresher)
+
+	stale.IAMSelectedOrgName = "org"
+	saveTestWorkspace(t, stale)
+
+	onDisk := loadTestWorkspace(t, "dev")
+	if onDisk.IAMAccessToken != "new" || onDisk.IAMRefreshToken != "refresh" || onDisk.IAMAccessTokenExpires != 200 {
+		t.Errorf("stale copy clobbered the refreshed token: %+v", onDisk)
+	}
+	if onDisk.IAMSelectedOrgName != "org" {
+		t.Errorf("expected change of the stale copy to be saved, got %q", onDisk.IAMSelectedOrgName)
+	}
+}
+
+func TestSaveKeepsIntentionallyReplacedTokens(t *testing.T) {
+	testWorkspaceRoot(t)
+	saveTestWorkspace(t, &workspaceConfig{Name: "dev", IAMAccessToken: "user", IAMRefreshToken: "refresh",
+		IAMAccessTokenExpires: 100, IAMUserUUID: "user-uuid"})
+
+	refresher := loadTestWorkspace(t, "dev")
+	login := loadTestWorkspace(t, "dev")
+	refresher.IAMAccessToken, refresher.IAMAccessTokenExpires = "user-refreshed", 300
+	saveTestWorkspace(t, refresher)
+
+	// A service login with a shorter lived token than the one on disk
+	login.IAMAccessToken, login.IAMRefreshToken, login.IAMAccessTokenExpires = "service", "", 200
+	login.IAMServiceKey, login.IAMUserUUID = "key", "service-uuid"
+	saveTestWorkspace(t, login)
+
+	onDisk := loadTestWorkspace(t, "dev")
+	if onDisk.IAMAccessToken != "service" || onDisk.IAMRefreshToken != "" || onDisk.IAMAccessTokenExpires != 200 ||
+		onDisk.IAMServiceKey != "key" || onDisk.IAMUserUUID != "service-uuid" {
+		t.Errorf("login was mixed with the tokens on disk: %+v", onDisk)
+	}
+}
+
+func TestSaveOverwritesWorkspaceNotLoadedFromDisk(t *testing.T) {
+	testWorkspaceRoot(t)
+	saveTestWorkspace(t, &workspaceConfig{Name: "dev", IAMAccessToken: "old", IAMAccessTokenExpires: 200,
+		UAAToken: "uaa", UAAAccessTokenExpires: 200})
+
+	// e.g. a workspace import --force of a bundle without credentials
+	saveTestWorkspace(t, &workspaceConfig{Name: "dev", DefaultRegion: "eu-west"})
+
+	onDisk := loadTestWorkspace(t, "dev")
+	if onDisk.IAMAccessToken != "" || onDisk.UAAToken != "" || onDisk.IAMAccessTokenExpires != 0 {
+		t.Errorf("old tokens survived the overwrite: %+v", onDisk)
+	}
+}
+
+func TestConcurrentSaves(t *testing.T) {
+	testWorkspaceRoot(t)
+	saveTestWorkspace(t, &workspaceConfig{Name: "dev"})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 1; i <= 10; i++ {
+		w := loadTestWorkspace(t, "dev")
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w.IAMAccessToken = fmt.Sprintf("token-%d", i)
+			w.IAMAccessTokenExpires = int64(i)
+			errs <- w.save()
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	onDisk := loadTestWorkspace(t, "dev")
+	if onDisk.IAMAccessToken != fmt.Sprintf("token-%d", onDisk.IAMAccessTokenExpires) {
+		t.Errorf("token and expiry on disk come from different saves: %+v", onDisk)
+	}
+}
+
+func TestSaveWaitsForWorkspaceLock(t *testing.T) {
+	testWorkspaceRoot(t)
+	w := &workspaceConfig{Name: "dev"}
+	unlock, err := lockWorkspaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- w.save()
+	}()
+	select {
+	case <-done:
+		t.Fatal("save completed while the workspace lock was held")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("save did not complete after the lock was released")
+	}
+}